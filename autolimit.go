@@ -0,0 +1,55 @@
+package goroutine_panic_helper
+
+import (
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// cgroupCPUMax is the cgroup v2 CPU quota file of the current container
+const cgroupCPUMax = "/sys/fs/cgroup/cpu.max"
+
+// LimitAuto sets the concurrency limit to the CPUs actually available: GOMAXPROCS,
+// lowered to the cgroup CPU quota rounded up when one is set, so containers with
+// fractional CPUs are not oversubscribed. See SetLimit.
+func LimitAuto() Option {
+	return func(gg *GoroutineGroup) {
+		gg.SetLimit(autoLimit(runtime.GOMAXPROCS(0), readCPUMax()))
+	}
+}
+
+func autoLimit(procs int, quota float64) int {
+	if quota > 0 && quota < float64(procs) {
+		procs = int(math.Ceil(quota))
+	}
+	return max(procs, 1)
+}
+
+// readCPUMax returns the cgroup CPU quota in CPUs, or 0 when there is none
+func readCPUMax() float64 {
+	data, err := os.ReadFile(cgroupCPUMax)
+	if err != nil {
+		return 0
+	}
+	return parseCPUMax(string(data))
+}
+
+// parseCPUMax parses the "<quota> <period>" format of cpu.max, where quota is
+// "max" when unlimited
+func parseCPUMax(s string) float64 {
+	fields := strings.Fields(s)
+	if len(fields) != 2 || fields[0] == "max" {
+		return 0
+	}
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || period <= 0 {
+		return 0
+	}
+	return quota / period
+}
@@ -0,0 +1,51 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+func TestParseCPUMax(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"max 100000\n", 0},
+		{"150000 100000\n", 1.5},
+		{"50000 100000", 0.5},
+		{"", 0},
+		{"garbage 100000", 0},
+		{"50000 0", 0},
+	}
+	for _, tt := range tests {
+		if got := parseCPUMax(tt.in); got != tt.want {
+			t.Errorf("parseCPUMax(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAutoLimit(t *testing.T) {
+	tests := []struct {
+		procs int
+		quota float64
+		want  int
+	}{
+		{8, 0, 8},
+		{8, 1.5, 2},
+		{8, 0.5, 1},
+		{2, 4, 2},
+	}
+	for _, tt := range tests {
+		if got := autoLimit(tt.procs, tt.quota); got != tt.want {
+			t.Errorf("autoLimit(%d, %v) = %d, want %d", tt.procs, tt.quota, got, tt.want)
+		}
+	}
+}
+
+func TestLimitAuto(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil, LimitAuto())
+	if n := cap(group.sem); n < 1 || n > runtime.GOMAXPROCS(0) {
+		t.Errorf("Unexpected automatic limit %d", n)
+	}
+}
@@ -0,0 +1,84 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func noopHandler(interface{}, []byte) {}
+
+// BenchmarkWaitGroup is the baseline: plain goroutines without recovery
+func BenchmarkWaitGroup(b *testing.B) {
+	var wg sync.WaitGroup
+	for i := 0; i < b.N; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkGroup_Go(b *testing.B) {
+	group := NewGoroutineGroup(context.Background(), noopHandler)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		group.Go(func(ctx context.Context) {})
+	}
+	group.Wait()
+}
+
+func BenchmarkGroup_GoLimited(b *testing.B) {
+	group := NewGoroutineGroup(context.Background(), noopHandler, WithLimit(runtime.GOMAXPROCS(0)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		group.Go(func(ctx context.Context) {})
+	}
+	group.Wait()
+}
+
+func BenchmarkGroup_GoParallel(b *testing.B) {
+	group := NewGoroutineGroup(context.Background(), noopHandler)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			group.Go(func(ctx context.Context) {})
+		}
+	})
+	group.Wait()
+}
+
+// panicTask is transient so benchmarks do not measure b.N retained errors and
+// stacks
+var panicTask = task{transient: true, fn: func(ctx context.Context) error {
+	panic("benchmark panic")
+}}
+
+func BenchmarkGroup_Panic(b *testing.B) {
+	group := NewGoroutineGroup(context.Background(), noopHandler)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		group.submit(panicTask)
+	}
+	group.Wait()
+}
+
+func BenchmarkGroup_PanicSampled(b *testing.B) {
+	group := NewGoroutineGroup(context.Background(), noopHandler, WithHandlerSampling(0.01))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		group.submit(panicTask)
+	}
+	group.Wait()
+}
+
+func BenchmarkGroup_PanicStackHead(b *testing.B) {
+	group := NewGoroutineGroup(context.Background(), noopHandler, WithErrorFormat(ErrorFormat{StackFrames: 3}))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		group.submit(panicTask)
+	}
+	group.Wait()
+}
@@ -0,0 +1,90 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CancelledError describes a task that ended after its context was cancelled or
+// its deadline passed, so reports can tell aborted tasks apart from failed ones
+type CancelledError struct {
+	Task string
+	// Cause is the cancellation cause of the task context, see context.Cause
+	Cause   error
+	Elapsed time.Duration
+	// err is the context error, context.Canceled or context.DeadlineExceeded
+	err error
+}
+
+func (e *CancelledError) Error() string {
+	if e.Task == "" {
+		return fmt.Sprintf("task aborted after %v: %v", e.Elapsed, e.Cause)
+	}
+	return fmt.Sprintf("task %q aborted after %v: %v", e.Task, e.Elapsed, e.Cause)
+}
+
+// Unwrap returns context.Canceled or context.DeadlineExceeded. The cause is not
+// unwrapped, so a task aborted because a sibling panicked does not match ErrPanic.
+func (e *CancelledError) Unwrap() error {
+	return e.err
+}
+
+func (e *CancelledError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Task    string        `json:"task,omitempty"`
+		Cause   string        `json:"cause"`
+		Elapsed time.Duration `json:"elapsed"`
+	}{e.Task, fmt.Sprint(e.Cause), e.Elapsed})
+}
+
+// RecordCancellations records a CancelledError for every task that returns after
+// the group's context is done, whether with nil or with the context's error. The
+// most recent ones are included in Snapshot, and context errors returned by GoErr
+// tasks are replaced by the CancelledError in the error returned from Wait.
+func RecordCancellations() Option {
+	return func(gg *GoroutineGroup) {
+		gg.recordCancels = true
+	}
+}
+
+// checkCancelled records a cancellation if the task named name, started at began
+// with ctx, ended because of it and returns the error to record for the task
+func (gg *GoroutineGroup) checkCancelled(ctx context.Context, name string, began time.Time, err error) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil || err != nil && !errors.Is(err, ctxErr) {
+		return err
+	}
+	cancelled := &CancelledError{
+		Task:    name,
+		Cause:   context.Cause(ctx),
+		Elapsed: time.Since(began),
+		err:     ctxErr,
+	}
+	gg.stats.recordCancelled(cancelled)
+	if err == nil {
+		return nil
+	}
+	return cancelled
+}
+
+func (s *groupStats) recordCancelled(e *CancelledError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cancelled) == recentPanicsSize {
+		copy(s.cancelled, s.cancelled[1:])
+		s.cancelled = s.cancelled[:recentPanicsSize-1]
+	}
+	s.cancelled = append(s.cancelled, e)
+}
+
+func (s *groupStats) recentCancelled() []*CancelledError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cancelled) == 0 {
+		return nil
+	}
+	return append([]*CancelledError(nil), s.cancelled...)
+}
@@ -0,0 +1,72 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGroup_RecordCancellations(t *testing.T) {
+	group, _ := NewGoroutineGroupWithContext(context.Background(), func(r interface{}, stack []byte) {}, RecordCancellations())
+	group.GoNamed("quiet", func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	group.GoErr(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+	err := group.Wait()
+
+	var cancelled *CancelledError
+	if !errors.As(err, &cancelled) {
+		t.Fatalf("Expected a CancelledError from Wait, got: %v", err)
+	}
+	if !errors.Is(cancelled, context.Canceled) || errors.Is(cancelled, ErrPanic) {
+		t.Errorf("CancelledError should match context.Canceled only, got: %v", cancelled)
+	}
+	if !errors.Is(cancelled.Cause, ErrPanic) {
+		t.Errorf("Expected the panic as cause, got: %v", cancelled.Cause)
+	}
+
+	records := group.Snapshot().Cancelled
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 cancellations, got %+v", records)
+	}
+	var named bool
+	for _, record := range records {
+		named = named || record.Task == "quiet"
+	}
+	if !named {
+		t.Errorf("Expected the named task among %+v", records)
+	}
+
+	data, err := json.Marshal(records[0])
+	if err != nil || !strings.Contains(string(data), `"cause":"panic recovery: boom"`) {
+		t.Errorf("Unexpected JSON %s, %v", data, err)
+	}
+}
+
+func TestGroup_RecordCancellations_Disabled(t *testing.T) {
+	group, _ := NewGoroutineGroupWithContext(context.Background(), func(r interface{}, stack []byte) {})
+	group.GoErr(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+	err := group.Wait()
+
+	var cancelled *CancelledError
+	if errors.As(err, &cancelled) {
+		t.Errorf("Expected no CancelledError without the option, got: %v", err)
+	}
+	if records := group.Snapshot().Cancelled; records != nil {
+		t.Errorf("Expected no cancellations, got %+v", records)
+	}
+}
@@ -0,0 +1,80 @@
+package goroutine_panic_helper
+
+import "sync"
+
+// WithCheckpoint makes Map, MapMerge, ForEach, ForEachSeq and ForEachSeq2 call
+// checkpoint after every n items that completed without an error, and once more at
+// the end if items completed since the last call. The watermark passed to
+// checkpoint is the number of leading items that all completed, so a crashed batch
+// job can resume from that index instead of reprocessing everything. Calls are
+// serialized and the watermark never decreases. Other groups ignore the option.
+func WithCheckpoint(n int, checkpoint func(watermark int)) Option {
+	return func(gg *GoroutineGroup) {
+		gg.checkpoint = &checkpointConfig{every: max(n, 1), fn: checkpoint}
+	}
+}
+
+type checkpointConfig struct {
+	every int
+	fn    func(watermark int)
+}
+
+// checkpointer tracks the completed items of one Map or ForEach call
+type checkpointer struct {
+	checkpointConfig
+
+	mu        sync.Mutex
+	watermark int
+	completed int
+	// ahead are the completed items above the watermark
+	ahead map[int]struct{}
+}
+
+// newCheckpointer returns the checkpointer for one helper call on gg, nil unless
+// WithCheckpoint is used
+func (gg *GoroutineGroup) newCheckpointer() *checkpointer {
+	if gg.checkpoint == nil {
+		return nil
+	}
+	return &checkpointer{checkpointConfig: *gg.checkpoint, ahead: make(map[int]struct{})}
+}
+
+// done returns the done callback of the task for item i
+func (c *checkpointer) done(i int) func(error) {
+	if c == nil {
+		return nil
+	}
+	return func(err error) {
+		if err == nil {
+			c.complete(i)
+		}
+	}
+}
+
+func (c *checkpointer) complete(i int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ahead[i] = struct{}{}
+	for {
+		if _, ok := c.ahead[c.watermark]; !ok {
+			break
+		}
+		delete(c.ahead, c.watermark)
+		c.watermark++
+	}
+	if c.completed++; c.completed%c.every == 0 {
+		c.fn(c.watermark)
+	}
+}
+
+// flush makes the final call for items completed since the last checkpoint
+func (c *checkpointer) flush() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.completed%c.every != 0 {
+		c.fn(c.watermark)
+	}
+}
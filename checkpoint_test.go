@@ -0,0 +1,43 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestWithCheckpoint(t *testing.T) {
+	var watermarks []int
+	err := ForEach(context.Background(), []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, func(ctx context.Context, i int) error {
+		if i == 4 {
+			return errors.New("item failed")
+		}
+		return nil
+	}, WithLimit(1), WithCheckpoint(3, func(watermark int) {
+		watermarks = append(watermarks, watermark)
+	}))
+
+	if err == nil {
+		t.Error("Expected the failure of item 4")
+	}
+	if want := []int{3, 4, 4}; !slices.Equal(watermarks, want) {
+		t.Errorf("Expected watermarks %v, got %v", want, watermarks)
+	}
+}
+
+func TestWithCheckpoint_Final(t *testing.T) {
+	var watermarks []int
+	_, err := Map(context.Background(), make([]int, 10), func(ctx context.Context, v int) (int, error) {
+		return v, nil
+	}, WithCheckpoint(4, func(watermark int) {
+		watermarks = append(watermarks, watermark)
+	}))
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if len(watermarks) != 3 || watermarks[2] != 10 {
+		t.Errorf("Expected two checkpoints and a final one at 10, got %v", watermarks)
+	}
+}
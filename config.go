@@ -0,0 +1,72 @@
+package goroutine_panic_helper
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Config is a declarative alternative to functional options for services that
+// configure groups from files. It can be unmarshalled from JSON or YAML. Options
+// that take callbacks, such as WithLeakSentinel, have no Config equivalent and are
+// passed alongside Options().
+type Config struct {
+	// HandlerSampleRate is the fraction of panics passed to the handler, see
+	// WithHandlerSampling. Nil disables sampling.
+	HandlerSampleRate *float64 `json:"handler_sample_rate,omitempty" yaml:"handler_sample_rate,omitempty"`
+	// ErrorPrefix replaces the "panic recovery" prefix of panic errors
+	ErrorPrefix string `json:"error_prefix,omitempty" yaml:"error_prefix,omitempty"`
+	// ErrorStackFrames is the number of stack frames appended to panic errors
+	ErrorStackFrames int `json:"error_stack_frames,omitempty" yaml:"error_stack_frames,omitempty"`
+	// CancelOnError cancels the group context on the first failure, see CancelOnError
+	CancelOnError bool `json:"cancel_on_error,omitempty" yaml:"cancel_on_error,omitempty"`
+	// Limit is the maximum number of tasks running concurrently, see SetLimit.
+	// Nil leaves the group unlimited.
+	Limit *int `json:"limit,omitempty" yaml:"limit,omitempty"`
+}
+
+// ConfigError describes an invalid Config field
+type ConfigError struct {
+	Field  string
+	Value  any
+	Reason string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid config %s=%v: %s", e.Field, e.Value, e.Reason)
+}
+
+// Validate returns a *ConfigError for every invalid field, joined
+func (c Config) Validate() error {
+	var errs []error
+	if c.HandlerSampleRate != nil && (*c.HandlerSampleRate < 0 || *c.HandlerSampleRate > 1) {
+		errs = append(errs, &ConfigError{Field: "handler_sample_rate", Value: *c.HandlerSampleRate, Reason: "must be between 0 and 1"})
+	}
+	if c.ErrorStackFrames < 0 {
+		errs = append(errs, &ConfigError{Field: "error_stack_frames", Value: c.ErrorStackFrames, Reason: "must not be negative"})
+	}
+	if c.Limit != nil && *c.Limit < 1 {
+		errs = append(errs, &ConfigError{Field: "limit", Value: *c.Limit, Reason: "must be positive"})
+	}
+	return errors.Join(errs...)
+}
+
+// Options validates c and converts it to options for NewGoroutineGroup
+func (c Config) Options() ([]Option, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	var opts []Option
+	if c.HandlerSampleRate != nil {
+		opts = append(opts, WithHandlerSampling(*c.HandlerSampleRate))
+	}
+	if c.ErrorPrefix != "" || c.ErrorStackFrames > 0 {
+		opts = append(opts, WithErrorFormat(ErrorFormat{Prefix: c.ErrorPrefix, StackFrames: c.ErrorStackFrames}))
+	}
+	if c.CancelOnError {
+		opts = append(opts, CancelOnError())
+	}
+	if c.Limit != nil {
+		opts = append(opts, WithLimit(*c.Limit))
+	}
+	return opts, nil
+}
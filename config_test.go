@@ -0,0 +1,55 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConfig_Options(t *testing.T) {
+	var cfg Config
+	if err := json.Unmarshal([]byte(`{"handler_sample_rate": 0.5, "error_prefix": "job failed", "cancel_on_error": true, "limit": 4}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	opts, err := cfg.Options()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {}, opts...)
+	if group.sampler == nil || group.sampler.rate != 0.5 {
+		t.Error("Expected handler sampling to be configured")
+	}
+	if !group.cancelOnError {
+		t.Error("Expected cancel on error to be configured")
+	}
+	if cap(group.sem) != 4 {
+		t.Errorf("Expected a limit of 4, got %d", cap(group.sem))
+	}
+	group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+	if err := group.Wait(); err == nil || !strings.HasPrefix(err.Error(), "job failed: boom") {
+		t.Errorf("Expected configured error prefix, got: %v", err)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	rate, limit := 1.5, 0
+	cfg := Config{HandlerSampleRate: &rate, ErrorStackFrames: -1, Limit: &limit}
+
+	err := cfg.Validate()
+	var cfgErr *ConfigError
+	if !errors.As(err, &cfgErr) {
+		t.Fatalf("Expected a ConfigError, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "handler_sample_rate") || !strings.Contains(err.Error(), "error_stack_frames") ||
+		!strings.Contains(err.Error(), "limit") {
+		t.Errorf("Expected all invalid fields to be reported, got: %v", err)
+	}
+	if _, err := cfg.Options(); err == nil {
+		t.Error("Expected Options to fail for an invalid config")
+	}
+}
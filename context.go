@@ -0,0 +1,34 @@
+package goroutine_panic_helper
+
+import "context"
+
+type groupContextKey struct{}
+
+// FromContext returns the group running the task that received ctx, or nil when
+// ctx does not belong to a group task. Tasks should start further goroutines
+// through it so their panics are recovered and Wait covers them as well.
+func FromContext(ctx context.Context) *GoroutineGroup {
+	gg, _ := ctx.Value(groupContextKey{}).(*GoroutineGroup)
+	return gg
+}
+
+// MustSpawnVia is like FromContext but panics when ctx does not belong to a group
+// task, for code paths that must never start an unsupervised goroutine.
+func MustSpawnVia(ctx context.Context) *GoroutineGroup {
+	gg := FromContext(ctx)
+	if gg == nil {
+		panic("goroutine_panic_helper: context does not belong to a group task")
+	}
+	return gg
+}
+
+// PanicsSoFar returns how many tasks of the group running the task that received
+// ctx have panicked since the group was created or last Reset, so cooperative
+// tasks can stop expensive work early without cancel-on-panic. It returns 0
+// outside of a group task.
+func PanicsSoFar(ctx context.Context) int64 {
+	if gg := FromContext(ctx); gg != nil {
+		return gg.stats.panicked.Load() - gg.batchPanicked.Load()
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromContext(t *testing.T) {
+	childDone := false
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		if FromContext(ctx) != group {
+			t.Error("FromContext did not return the running group")
+		}
+		MustSpawnVia(ctx).Go(func(ctx context.Context) {
+			time.Sleep(20 * time.Millisecond)
+			childDone = true
+			panic("child panic")
+		})
+	})
+
+	err := group.Wait()
+	if !childDone {
+		t.Error("Wait returned before the child task finished")
+	}
+	if err == nil || !strings.Contains(err.Error(), "child panic") {
+		t.Errorf("Expected child panic error, got: %v", err)
+	}
+}
+
+func TestFromContext_Outside(t *testing.T) {
+	if FromContext(context.Background()) != nil {
+		t.Error("Expected nil group outside of a task")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustSpawnVia to panic outside of a task")
+		}
+	}()
+	MustSpawnVia(context.Background())
+}
+
+func TestPanicsSoFar(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+	group.Wait()
+
+	group.Go(func(ctx context.Context) {
+		if n := PanicsSoFar(ctx); n != 1 {
+			t.Errorf("Expected 1 panic so far, got %d", n)
+		}
+	})
+	group.Wait()
+
+	if n := PanicsSoFar(context.Background()); n != 0 {
+		t.Errorf("Expected 0 outside of a group task, got %d", n)
+	}
+}
+
+func TestPanicsSoFar_Reset(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+	group.Wait()
+	group.Reset()
+
+	group.Go(func(ctx context.Context) {
+		if n := PanicsSoFar(ctx); n != 0 {
+			t.Errorf("Expected no panics in the new batch, got %d", n)
+		}
+	})
+	group.Wait()
+}
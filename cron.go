@@ -0,0 +1,37 @@
+package goroutine_panic_helper
+
+import "context"
+
+// Job is the job interface of cron libraries such as robfig/cron
+type Job interface {
+	Run()
+}
+
+// JobFunc adapts a function to Job
+type JobFunc func()
+
+func (f JobFunc) Run() {
+	f()
+}
+
+// WrapJob returns a Job that runs j as a task of gg named name, giving scheduled
+// jobs the group's panic recovery and reporting without changing the scheduler.
+// Run blocks until j finishes, so scheduler wrappers such as skip-if-still-running
+// keep working. Panics are reported to gg's handler and appear in its Snapshot,
+// but are not retained for Wait, since a scheduler's group typically lives as long
+// as the process and would otherwise keep every panic and its stack forever.
+func (gg *GoroutineGroup) WrapJob(name string, j Job) Job {
+	return JobFunc(func() {
+		done := make(chan struct{})
+		gg.submit(task{
+			name:      name,
+			transient: true,
+			fn: func(ctx context.Context) error {
+				j.Run()
+				return nil
+			},
+			done: func(error) { close(done) },
+		})
+		<-done
+	})
+}
@@ -0,0 +1,37 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGroup_WrapJob(t *testing.T) {
+	var handledTask string
+	group := NewGoroutineGroup(context.Background(), nil, WithTaskPanicHandler(func(task string, r interface{}, stack []byte) {
+		handledTask = task
+	}))
+
+	runs := 0
+	job := group.WrapJob("cleanup", JobFunc(func() {
+		runs++
+		if runs == 2 {
+			panic("cleanup failed")
+		}
+	}))
+
+	job.Run()
+	job.Run()
+	if runs != 2 {
+		t.Errorf("Expected Run to block until the job finished, got %d runs", runs)
+	}
+	if handledTask != "cleanup" {
+		t.Errorf("Expected panic of job cleanup to be handled, got %q", handledTask)
+	}
+	if snapshot := group.Snapshot(); snapshot.Panicked != 1 || !strings.Contains(snapshot.RecentPanics[0].Value, "cleanup failed") {
+		t.Errorf("Expected job panic in snapshot, got %+v", snapshot)
+	}
+	if err := group.Wait(); err != nil {
+		t.Errorf("Expected job panics not to be retained, got: %v", err)
+	}
+}
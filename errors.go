@@ -0,0 +1,71 @@
+package goroutine_panic_helper
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrPanic is matched by errors.Is for every panic-derived error, to tell
+// crash-style failures apart from errors returned by tasks
+var ErrPanic = errors.New("panic")
+
+// PanicError is the error a recovered panic is converted to. Use errors.As to get
+// the original panic value and stack without parsing the message.
+type PanicError struct {
+	task  string
+	value any
+	stack []byte
+	msg   string
+}
+
+func (e *PanicError) Error() string {
+	return e.msg
+}
+
+// Task returns the name of the task that panicked, empty for unnamed tasks
+func (e *PanicError) Task() string {
+	return e.task
+}
+
+// Value returns the value the task panicked with
+func (e *PanicError) Value() any {
+	return e.value
+}
+
+// Stack returns the stack trace captured when the panic was recovered
+func (e *PanicError) Stack() []byte {
+	return e.stack
+}
+
+// Unwrap returns the panic value if it is an error, so errors.Is and errors.As
+// see errors passed to panic
+func (e *PanicError) Unwrap() error {
+	err, _ := e.value.(error)
+	return err
+}
+
+// Is reports whether target is ErrPanic
+func (e *PanicError) Is(target error) bool {
+	return target == ErrPanic
+}
+
+// PanicValue is a structured panic value for intentional panics. Groups record
+// its code and fields in Snapshot, LogfmtHandler writes them as separate keys, and
+// errors.As finds it in the error Wait returns.
+type PanicValue struct {
+	Code    string
+	Message string
+	Fields  map[string]any
+}
+
+func (v *PanicValue) Error() string {
+	if v.Code == "" {
+		return v.Message
+	}
+	return v.Code + ": " + v.Message
+}
+
+// Panicf panics with a PanicValue of the given code and formatted message
+func Panicf(code, format string, args ...any) {
+	panic(&PanicValue{Code: code, Message: fmt.Sprintf(format, args...)})
+}
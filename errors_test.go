@@ -0,0 +1,94 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPanicError(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		panic(42)
+	})
+
+	err := group.Wait()
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected a PanicError, got: %v", err)
+	}
+	if panicErr.Value() != 42 {
+		t.Errorf("Expected panic value 42, got %v", panicErr.Value())
+	}
+	if !strings.Contains(string(panicErr.Stack()), "errors_test.go") {
+		t.Errorf("Expected stack to contain the panicking function, got:\n%s", panicErr.Stack())
+	}
+	if err.Error() != "panic recovery: 42" {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+}
+
+func TestPanicError_WrapsErrorValue(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		panic(io.ErrUnexpectedEOF)
+	})
+
+	err := group.Wait()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected error to wrap the panic value, got: %v", err)
+	}
+	if err.Error() != "panic recovery: unexpected EOF" {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+}
+
+func TestErrPanic(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		panic(io.EOF)
+	})
+	if err := group.Wait(); !errors.Is(err, ErrPanic) || !errors.Is(err, io.EOF) {
+		t.Errorf("Expected error to match ErrPanic and the panic value, got: %v", err)
+	}
+
+	group = NewGoroutineGroup(context.Background(), nil)
+	group.GoErr(func(ctx context.Context) error {
+		return io.EOF
+	})
+	if err := group.Wait(); errors.Is(err, ErrPanic) {
+		t.Error("Returned error must not match ErrPanic")
+	}
+}
+
+func TestPanicValue(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		panic(&PanicValue{Code: "E_QUOTA", Message: "quota exceeded", Fields: map[string]any{"tenant": "acme"}})
+	})
+	group.Go(func(ctx context.Context) {
+		Panicf("E_INPUT", "bad id %d", 7)
+	})
+	err := group.Wait()
+
+	var value *PanicValue
+	if !errors.As(err, &value) {
+		t.Fatalf("Expected a PanicValue, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "panic recovery: E_INPUT: bad id 7") {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+
+	codes := map[string]bool{}
+	for _, record := range group.Snapshot().RecentPanics {
+		codes[record.Code] = true
+		if record.Code == "E_QUOTA" && record.Fields["tenant"] != "acme" {
+			t.Errorf("Expected fields in the record, got %+v", record)
+		}
+	}
+	if !codes["E_QUOTA"] || !codes["E_INPUT"] {
+		t.Errorf("Expected both codes in the snapshot, got %v", codes)
+	}
+}
@@ -0,0 +1,99 @@
+package goroutine_panic_helper
+
+import (
+	"sync"
+	"time"
+)
+
+// TaskEventKind is the kind of a task lifecycle event
+type TaskEventKind string
+
+const (
+	EventStart  TaskEventKind = "start"
+	EventFinish TaskEventKind = "finish"
+	EventError  TaskEventKind = "error"
+	EventPanic  TaskEventKind = "panic"
+)
+
+// TaskEvent is one task lifecycle event recorded by WithEventLog
+type TaskEvent struct {
+	Time time.Time     `json:"time"`
+	Task int64         `json:"task"`
+	Name string        `json:"name,omitempty"`
+	Kind TaskEventKind `json:"kind"`
+	// Detail is the error message or panic value for error and panic events
+	Detail string `json:"detail,omitempty"`
+}
+
+// WithEventLog keeps the last size task lifecycle events of the group in a ring
+// buffer, so post-incident analysis can see what the group was doing right before
+// a failure. Tasks are identified by their submission order, starting at 1. The
+// events are available from RecentEvents and are included in Snapshot.
+func WithEventLog(size int) Option {
+	return func(gg *GoroutineGroup) {
+		if size > 0 {
+			gg.events = &eventLog{buf: make([]TaskEvent, size)}
+		}
+	}
+}
+
+// RecentEvents returns the recorded task events, oldest first. It returns nil
+// unless the group was created with WithEventLog.
+func (gg *GoroutineGroup) RecentEvents() []TaskEvent {
+	return gg.events.snapshot()
+}
+
+// WithTaskObserver calls observe with every task lifecycle event as it happens,
+// on the task's goroutine, for live displays such as TerminalReporter. observe
+// must be fast and must not panic.
+func WithTaskObserver(observe func(TaskEvent)) Option {
+	return func(gg *GoroutineGroup) {
+		gg.observer = observe
+	}
+}
+
+// event records a task lifecycle event in the event log and passes it to the
+// observer, if either is configured
+func (gg *GoroutineGroup) event(task int64, name string, kind TaskEventKind, detail string) {
+	if gg.events == nil && gg.observer == nil {
+		return
+	}
+	event := TaskEvent{Time: time.Now(), Task: task, Name: name, Kind: kind, Detail: detail}
+	gg.events.record(event)
+	if gg.observer != nil {
+		gg.observer(event)
+	}
+}
+
+type eventLog struct {
+	mu   sync.Mutex
+	buf  []TaskEvent
+	next int
+	full bool
+}
+
+func (l *eventLog) record(event TaskEvent) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf[l.next] = event
+	l.next = (l.next + 1) % len(l.buf)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+func (l *eventLog) snapshot() []TaskEvent {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.full {
+		return append([]TaskEvent(nil), l.buf[:l.next]...)
+	}
+	return append(append([]TaskEvent(nil), l.buf[l.next:]...), l.buf[:l.next]...)
+}
@@ -0,0 +1,50 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGroup_EventLog(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {}, WithEventLog(4))
+	group.Go(func(ctx context.Context) {})
+	group.Wait()
+	group.GoErr(func(ctx context.Context) error {
+		return errors.New("task failed")
+	})
+	group.Wait()
+	group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+	group.Wait()
+
+	events := group.RecentEvents()
+	want := []TaskEvent{
+		{Task: 2, Kind: EventStart},
+		{Task: 2, Kind: EventError, Detail: "task failed"},
+		{Task: 3, Kind: EventStart},
+		{Task: 3, Kind: EventPanic, Detail: "boom"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %+v", len(want), events)
+	}
+	for i, event := range events {
+		if event.Task != want[i].Task || event.Kind != want[i].Kind || event.Detail != want[i].Detail {
+			t.Errorf("Event %d: expected %+v, got %+v", i, want[i], event)
+		}
+	}
+	if len(group.Snapshot().RecentEvents) != 4 {
+		t.Error("Expected events in snapshot")
+	}
+}
+
+func TestGroup_EventLog_Disabled(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {})
+	group.Wait()
+
+	if events := group.RecentEvents(); events != nil {
+		t.Errorf("Expected no events without WithEventLog, got %+v", events)
+	}
+}
@@ -0,0 +1,82 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FallbackPath reports which function of a WithFallback task succeeded
+type FallbackPath int
+
+const (
+	// PathNone means both the primary and the fallback failed
+	PathNone FallbackPath = iota
+	PathPrimary
+	PathFallback
+)
+
+func (p FallbackPath) String() string {
+	switch p {
+	case PathPrimary:
+		return "primary"
+	case PathFallback:
+		return "fallback"
+	default:
+		return "none"
+	}
+}
+
+// WithFallback returns a task that runs primary with a deadline of timeout and, if
+// it panics, returns an error, or times out, runs fallback instead. Both are
+// panic-protected; panics are passed to handler, or DefaultPanicHandler if nil.
+// When primary times out its context is cancelled and it is left to return on its
+// own. If ctx is done before the fallback would run, it is not run and the error
+// matches ctx.Err().
+func WithFallback(primary, fallback func(context.Context) error, timeout time.Duration, handler PanicHandler) func(context.Context) (FallbackPath, error) {
+	if handler == nil {
+		handler = DefaultPanicHandler
+	}
+	return func(ctx context.Context) (FallbackPath, error) {
+		primaryCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		done := make(chan error, 1)
+		go func() {
+			var err error
+			if panicErr := runProtected(handler, func() {
+				err = primary(primaryCtx)
+			}); panicErr != nil {
+				err = panicErr
+			}
+			done <- err
+		}()
+
+		var primaryErr error
+		select {
+		case primaryErr = <-done:
+			if primaryErr == nil {
+				return PathPrimary, nil
+			}
+		case <-primaryCtx.Done():
+			if ctx.Err() == nil {
+				primaryErr = fmt.Errorf("primary timed out: %w", primaryCtx.Err())
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return PathNone, errors.Join(primaryErr, err)
+		}
+
+		var fallbackErr error
+		if panicErr := runProtected(handler, func() {
+			fallbackErr = fallback(ctx)
+		}); panicErr != nil {
+			fallbackErr = panicErr
+		}
+		if fallbackErr != nil {
+			return PathNone, errors.Join(primaryErr, fallbackErr)
+		}
+		return PathFallback, nil
+	}
+}
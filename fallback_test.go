@@ -0,0 +1,96 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithFallback(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("primary failed") }
+	panicking := func(ctx context.Context) error { panic("primary panic") }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name     string
+		primary  func(context.Context) error
+		fallback func(context.Context) error
+		want     FallbackPath
+	}{
+		{"primary succeeds", ok, failing, PathPrimary},
+		{"primary errors", failing, ok, PathFallback},
+		{"primary panics", panicking, ok, PathFallback},
+		{"primary times out", slow, ok, PathFallback},
+		{"both fail", failing, panicking, PathNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := WithFallback(tt.primary, tt.fallback, 20*time.Millisecond, noopHandler)(context.Background())
+			if path != tt.want {
+				t.Errorf("Expected path %v, got %v", tt.want, path)
+			}
+			if (err != nil) != (tt.want == PathNone) {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithFallback_BothFailError(t *testing.T) {
+	_, err := WithFallback(func(ctx context.Context) error {
+		return errors.New("primary failed")
+	}, func(ctx context.Context) error {
+		panic("fallback panic")
+	}, time.Second, noopHandler)(context.Background())
+
+	if err == nil || !strings.Contains(err.Error(), "primary failed") || !strings.Contains(err.Error(), "fallback panic") {
+		t.Errorf("Expected both failures in error, got: %v", err)
+	}
+}
+
+func TestWithFallback_Handler(t *testing.T) {
+	var handled []interface{}
+	handler := func(r interface{}, stack []byte) {
+		handled = append(handled, r)
+	}
+	path, err := WithFallback(func(ctx context.Context) error {
+		panic("primary panic")
+	}, func(ctx context.Context) error {
+		return nil
+	}, time.Second, handler)(context.Background())
+
+	if path != PathFallback || err != nil {
+		t.Errorf("Expected fallback to succeed, got %v, %v", path, err)
+	}
+	if len(handled) != 1 || handled[0] != "primary panic" {
+		t.Errorf("Expected primary panic passed to handler, got %v", handled)
+	}
+}
+
+func TestWithFallback_ParentCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ran := false
+	task := WithFallback(func(ctx context.Context) error {
+		cancel()
+		<-ctx.Done()
+		time.Sleep(time.Millisecond)
+		return nil
+	}, func(ctx context.Context) error {
+		ran = true
+		return nil
+	}, time.Second, noopHandler)
+
+	path, err := task(ctx)
+	if path != PathNone || !errors.Is(err, context.Canceled) || strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected the cancellation to be reported, got %v, %v", path, err)
+	}
+	if ran {
+		t.Error("Expected the fallback not to run after the caller's ctx was done")
+	}
+}
@@ -0,0 +1,39 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Flusher is implemented by panic reporters that deliver reports asynchronously,
+// such as Sentry clients, webhooks, or queues
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+type flusher struct {
+	f       Flusher
+	timeout time.Duration
+}
+
+// WithFlusher makes Wait flush f before returning, waiting at most timeout, so
+// reports aren't lost when the process exits right after the group completes.
+// Flush errors are joined into the error returned from Wait.
+func WithFlusher(f Flusher, timeout time.Duration) Option {
+	return func(gg *GoroutineGroup) {
+		if f != nil {
+			gg.flushers = append(gg.flushers, flusher{f: f, timeout: timeout})
+		}
+	}
+}
+
+func (gg *GoroutineGroup) flush() error {
+	var errs []error
+	for _, fl := range gg.flushers {
+		ctx, cancel := context.WithTimeout(context.Background(), fl.timeout)
+		errs = append(errs, fl.f.Flush(ctx))
+		cancel()
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,59 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type queueReporter struct {
+	mu      sync.Mutex
+	pending []interface{}
+	sent    []interface{}
+	err     error
+}
+
+func (q *queueReporter) handle(r interface{}, stack []byte) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, r)
+}
+
+func (q *queueReporter) Flush(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("flush without deadline")
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.sent = append(q.sent, q.pending...)
+	q.pending = nil
+	return q.err
+}
+
+func TestGroup_WithFlusher(t *testing.T) {
+	reporter := &queueReporter{}
+	group := NewGoroutineGroup(context.Background(), reporter.handle, WithFlusher(reporter, time.Second))
+	group.Go(func(ctx context.Context) {
+		panic("flushed panic")
+	})
+
+	if err := group.Wait(); err == nil {
+		t.Error("Expected an error from Wait(), got nil")
+	}
+	if len(reporter.sent) != 1 || len(reporter.pending) != 0 {
+		t.Errorf("Expected report to be flushed, sent=%v pending=%v", reporter.sent, reporter.pending)
+	}
+}
+
+func TestGroup_WithFlusher_Error(t *testing.T) {
+	flushErr := errors.New("flush failed")
+	reporter := &queueReporter{err: flushErr}
+	group := NewGoroutineGroup(context.Background(), reporter.handle, WithFlusher(reporter, time.Second))
+	group.Go(func(ctx context.Context) {})
+
+	if err := group.Wait(); !errors.Is(err, flushErr) {
+		t.Errorf("Expected flush error from Wait(), got: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package goroutine_panic_helper
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultErrorPrefix = "panic recovery"
+
+// ErrorFormat controls the message of errors converted from panics, for log-based
+// alerting that keys on specific message shapes
+type ErrorFormat struct {
+	// Prefix replaces the default "panic recovery" prefix
+	Prefix string
+	// StackFrames appends the innermost StackFrames frames of the panicking code
+	// to the message, on the same line
+	StackFrames int
+	// OmitTask leaves the task name, as in `in task "fetch"`, out of the message.
+	// PanicError.Task still returns it.
+	OmitTask bool
+}
+
+// WithErrorFormat sets the format of errors converted from panics
+func WithErrorFormat(format ErrorFormat) Option {
+	return func(gg *GoroutineGroup) {
+		gg.errFormat = format
+	}
+}
+
+func (f ErrorFormat) prefix() string {
+	if f.Prefix == "" {
+		return defaultErrorPrefix
+	}
+	return f.Prefix
+}
+
+func (f ErrorFormat) toError(task string, recovery any, stack []byte) error {
+	msg := fmt.Sprintf("%s: %v", f.prefix(), recovery)
+	if task != "" && !f.OmitTask {
+		msg = fmt.Sprintf("%s in task %q: %v", f.prefix(), task, recovery)
+	}
+	if f.StackFrames > 0 {
+		if head := stackHead(stack, f.StackFrames); head != "" {
+			msg += " (at " + head + ")"
+		}
+	}
+	return &PanicError{task: task, value: recovery, stack: stack, msg: msg}
+}
+
+// stackHead returns the innermost n frames below the panic call in a stack
+// formatted by debug.Stack, as "function file:line" joined with " <- ".
+func stackHead(stack []byte, n int) string {
+	frames := panicFrames(stack, n)
+	head := make([]string, len(frames))
+	for i, frame := range frames {
+		head[i] = frame.function + " " + frame.location
+	}
+	return strings.Join(head, " <- ")
+}
+
+type stackFrame struct {
+	function string
+	location string
+}
+
+// panicFrames parses up to n non-runtime frames below the panic call in a stack
+// formatted by debug.Stack, innermost first.
+func panicFrames(stack []byte, n int) []stackFrame {
+	lines := strings.Split(strings.TrimSpace(string(stack)), "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "panic(") {
+			start = i + 2
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+
+	var frames []stackFrame
+	for i := start; i+1 < len(lines) && len(frames) < n; i += 2 {
+		function := lines[i]
+		if strings.HasSuffix(function, ")") {
+			function = function[:strings.LastIndex(function, "(")]
+		}
+		if strings.HasPrefix(function, "runtime.") {
+			continue
+		}
+		location := strings.TrimSpace(lines[i+1])
+		if offset := strings.LastIndex(location, " +0x"); offset >= 0 {
+			location = location[:offset]
+		}
+		frames = append(frames, stackFrame{function: function, location: location})
+	}
+	return frames
+}
@@ -0,0 +1,65 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func panicInParse() {
+	var m map[string]int
+	m["key"] = 1
+}
+
+func TestGroup_ErrorFormat(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {},
+		WithErrorFormat(ErrorFormat{Prefix: "task crashed", StackFrames: 1}))
+	group.Go(func(ctx context.Context) {
+		panicInParse()
+	})
+
+	err := group.Wait()
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "task crashed: assignment to entry in nil map") {
+		t.Errorf("Unexpected error prefix: %v", msg)
+	}
+	if !strings.Contains(msg, "(at github.com/onurburak9/goroutine-panic-helper.panicInParse ") ||
+		!strings.Contains(msg, "format_test.go:") {
+		t.Errorf("Expected stack head in error message, got: %v", msg)
+	}
+	if strings.Contains(msg, "\n") {
+		t.Errorf("Expected single-line error message, got: %q", msg)
+	}
+}
+
+func TestGroup_ErrorFormat_Default(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+
+	if err := group.Wait(); err == nil || err.Error() != "panic recovery: boom" {
+		t.Errorf("Unexpected default error: %v", err)
+	}
+}
+
+func TestGroup_ErrorFormat_OmitTask(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {},
+		WithErrorFormat(ErrorFormat{OmitTask: true}))
+	group.GoNamed("fetch", func(ctx context.Context) {
+		panic("boom")
+	})
+
+	err := group.Wait()
+	if err == nil || err.Error() != "panic recovery: boom" {
+		t.Errorf("Expected the message without the task name, got: %v", err)
+	}
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Task() != "fetch" {
+		t.Errorf("Expected the task name on the PanicError, got: %v", err)
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/onurburak9/goroutine-panic-helper
 
-go 1.22.4
+go 1.23
@@ -2,66 +2,644 @@ package goroutine_panic_helper
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"runtime/debug"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type GoroutineGroup struct {
-	wg      sync.WaitGroup
-	ctx     context.Context
-	handler PanicHandler
-	errOnce sync.Once
-	err     error
+	wg sync.WaitGroup
+	// parent is the context the group was created with, base the parent of the
+	// task context, carrying the group
+	parent context.Context
+	base   context.Context
+	cancel context.CancelCauseFunc
+	// ownCancel is set when cancel was derived by the group for CancelOnError
+	ownCancel bool
+	handler   PanicHandler
+	errMu     sync.Mutex
+	errs      []error
+	sem       chan struct{}
+	// sharedSem is set when sem belongs to a Limiter
+	sharedSem bool
+
+	cancelOnPanic bool
+	cancelOnError bool
+	recordCancels bool
+	// taskHandler replaces handler when set
+	taskHandler TaskPanicHandler
+	repanic     func(any) bool
+	// crash is set by WithCrashOnPanic
+	crash func()
+
+	stopping     chan struct{}
+	stoppingOnce sync.Once
+
+	// idle is closed when running drops to zero, and progress when any task
+	// finishes, guarded by idleMu. The task context is derived from base when the
+	// first task starts and released once none is running, so Shutdown can cancel
+	// it without the group staying registered with its parent.
+	idleMu   sync.Mutex
+	running  int
+	idle     chan struct{}
+	progress chan struct{}
+	ctx      context.Context
+	stop     context.CancelCauseFunc
+
+	// batchStarted, batchCompleted and batchPanicked are the task counts at the
+	// last Reset
+	batchStarted   atomic.Int64
+	batchCompleted atomic.Int64
+	batchPanicked  atomic.Int64
+
+	sentinel  *leakSentinel
+	sampler   *panicSampler
+	flushers  []flusher
+	panicRate *panicRate
+	leakCheck *goroutineLeakCheck
+	errFormat ErrorFormat
+	stats     groupStats
+	events    *eventLog
+	observer  func(TaskEvent)
+	launcher  Launcher
+	// submissions is nil unless WithSubmissionProfile is used
+	submissions *submissionProfile
+	// checkpoint is nil unless WithCheckpoint is used
+	checkpoint *checkpointConfig
+	// successRate is nil unless WithSuccessRate is used
+	successRate *successRates
 }
 
 // PanicHandler is a function type that defines how panics should be handled
 type PanicHandler func(interface{}, []byte)
 
-func NewGoroutineGroup(ctx context.Context, handler PanicHandler) *GoroutineGroup {
-	gg := &GoroutineGroup{ctx: ctx}
+// TaskPanicHandler is like PanicHandler but also receives the name of the task
+// that panicked, empty for tasks not started with GoNamed
+type TaskPanicHandler func(task string, r interface{}, stack []byte)
+
+// Option configures optional behavior of a GoroutineGroup
+type Option func(*GoroutineGroup)
+
+func NewGoroutineGroup(ctx context.Context, handler PanicHandler, opts ...Option) *GoroutineGroup {
+	gg := &GoroutineGroup{}
 	if handler == nil {
 		handler = DefaultPanicHandler
 	}
 	gg.handler = handler
+	for _, opt := range opts {
+		opt(gg)
+	}
+	if gg.repanic == nil && devMode() {
+		gg.repanic = IsRuntimeError
+	}
+	gg.parent = ctx
+	if gg.cancelOnError && gg.cancel == nil {
+		ctx, gg.cancel = context.WithCancelCause(ctx)
+		gg.ownCancel = true
+	}
+	gg.stopping = make(chan struct{})
+	gg.base = context.WithValue(ctx, groupContextKey{}, gg)
+	if gg.sentinel == nil {
+		if hook := strictWaitHook.Load(); hook != nil {
+			gg.sentinel = &leakSentinel{hook: *hook}
+		}
+	}
+	if gg.sentinel != nil {
+		gg.sentinel.arm()
+	}
 	return gg
 }
 
-func (gg *GoroutineGroup) Go(fn func(context.Context)) {
+// NewGoroutineGroupWithContext is like NewGoroutineGroup but also returns a context
+// derived from ctx that is cancelled as soon as any task panics, or when Wait
+// returns, whichever happens first. Its cause is the panic-derived error.
+func NewGoroutineGroupWithContext(ctx context.Context, handler PanicHandler, opts ...Option) (*GoroutineGroup, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	withCancel := func(gg *GoroutineGroup) {
+		gg.cancel = cancel
+		gg.cancelOnPanic = true
+	}
+	gg := NewGoroutineGroup(ctx, handler, append([]Option{withCancel}, opts...)...)
+	return gg, ctx
+}
+
+// WithTaskPanicHandler reports panics to h instead of the group's PanicHandler, so
+// the handler learns which named task panicked
+func WithTaskPanicHandler(h TaskPanicHandler) Option {
+	return func(gg *GoroutineGroup) {
+		gg.taskHandler = h
+	}
+}
+
+// CancelOnError makes the group cancel the context passed to its tasks as soon as
+// the first task fails, whether by returning an error from GoErr or by panicking,
+// matching errgroup semantics. The panic handler still sees every panic.
+func CancelOnError() Option {
+	return func(gg *GoroutineGroup) {
+		gg.cancelOnError = true
+	}
+}
+
+// Go runs fn on a new goroutine, recovering its panics, and returns a handle to
+// wait on it alone. The context passed to fn is cancelled as soon as no task of
+// the group is running any more, much like errgroup's, so goroutines and callbacks
+// that outlive the task must not keep it.
+func (gg *GoroutineGroup) Go(fn func(context.Context)) *Task {
+	return gg.GoErr(func(ctx context.Context) error {
+		fn(ctx)
+		return nil
+	})
+}
+
+// GoErr runs fn like Go and additionally records the error it returns. Returned
+// errors and panic-derived errors are surfaced by Wait the same way. fn's context
+// is cancelled once the group drains, as with Go.
+func (gg *GoroutineGroup) GoErr(fn func(context.Context) error) *Task {
+	handle := newTask()
+	gg.submit(task{fn: fn, handle: handle})
+	return handle
+}
+
+// GoNamed runs fn like Go under the given name. The name is passed to a
+// TaskPanicHandler and included in the error Wait returns if fn panics, so the
+// failing task can be identified without reading stacks.
+func (gg *GoroutineGroup) GoNamed(name string, fn func(context.Context)) *Task {
+	handle := newTask()
+	gg.submit(task{name: name, handle: handle, fn: func(ctx context.Context) error {
+		fn(ctx)
+		return nil
+	}})
+	return handle
+}
+
+// GoWithHandler runs fn like Go but reports its panics to handler instead of the
+// group's handler, for example to tag reports with the payload being processed
+func (gg *GoroutineGroup) GoWithHandler(fn func(context.Context), handler PanicHandler) *Task {
+	handle := newTask()
+	gg.submit(task{handler: handler, handle: handle, fn: func(ctx context.Context) error {
+		fn(ctx)
+		return nil
+	}})
+	return handle
+}
+
+// ErrTaskTimeout is matched by errors.Is for errors recorded by GoWithTimeout
+var ErrTaskTimeout = errors.New("task timed out")
+
+// GoWithTimeout runs fn like Go with a context that times out after d. If the
+// timeout fires before fn returns, an error matching ErrTaskTimeout is recorded.
+func (gg *GoroutineGroup) GoWithTimeout(d time.Duration, fn func(context.Context)) *Task {
+	return gg.GoErr(func(ctx context.Context) error {
+		timeout := fmt.Errorf("%w after %v", ErrTaskTimeout, d)
+		ctx, cancel := context.WithTimeoutCause(ctx, d, timeout)
+		defer cancel()
+		fn(ctx)
+		if context.Cause(ctx) == timeout {
+			return timeout
+		}
+		return nil
+	})
+}
+
+// GoWith runs fn on gg like Go, passing arg explicitly instead of having fn
+// capture it. Methods cannot have type parameters, so this is a function.
+func GoWith[T any](gg *GoroutineGroup, arg T, fn func(ctx context.Context, arg T)) *Task {
+	return gg.Go(func(ctx context.Context) {
+		fn(ctx, arg)
+	})
+}
+
+// task is one submission to the group
+type task struct {
+	name string
+	fn   func(context.Context) error
+	// handler replaces the group's handlers for this task when set
+	handler PanicHandler
+	// transient failures are reported but not retained for Wait, for tasks of
+	// groups that live as long as the process
+	transient bool
+	// done is called with the task's error, returned or panic-derived, after fn
+	// returned and any panic was handled
+	done func(err error)
+	// handle is the Task returned to the caller, if any
+	handle *Task
+	// submitted is when the task was submitted, before waiting for a slot, and
+	// started when it began running
+	submitted time.Time
+	started   time.Time
+}
+
+// submit starts t, blocking while the group is at its limit
+func (gg *GoroutineGroup) submit(t task) {
+	t.submitted = time.Now()
+	if t.handle != nil {
+		t.handle.submitted = t.submitted
+	}
+	sem := gg.sem
+	if sem != nil {
+		sem <- struct{}{}
+	}
+	gg.start(sem, t)
+}
+
+// TryGo runs fn like Go if the group is below its limit and reports whether it
+// did. Unlike Go it never blocks, so latency-sensitive callers can shed load.
+func (gg *GoroutineGroup) TryGo(fn func(context.Context)) bool {
+	_, ok := gg.TryGoTask(fn)
+	return ok
+}
+
+// TryGoTask is like TryGo but also returns a handle to the task, nil if it was not
+// started
+func (gg *GoroutineGroup) TryGoTask(fn func(context.Context)) (*Task, bool) {
+	submitted := time.Now()
+	sem := gg.sem
+	if sem != nil {
+		select {
+		case sem <- struct{}{}:
+		default:
+			return nil, false
+		}
+	}
+	handle := newTask()
+	handle.submitted = submitted
+	gg.start(sem, task{submitted: submitted, handle: handle, fn: func(ctx context.Context) error {
+		fn(ctx)
+		return nil
+	}})
+	return handle, true
+}
+
+// start runs t through the group's Launcher; sem is released when it finishes, if
+// not nil
+func (gg *GoroutineGroup) start(sem chan struct{}, t task) {
+	if gg.sentinel != nil {
+		gg.sentinel.started.Add(1)
+	}
+	gg.submissions.record()
+	id := gg.stats.started.Add(1)
 	gg.wg.Add(1)
-	go func() {
-		defer gg.wg.Done()
+	gg.idleMu.Lock()
+	if gg.running == 0 {
+		gg.idle = make(chan struct{})
+		gg.ctx, gg.stop = context.WithCancelCause(gg.base)
+	}
+	gg.running++
+	ctx := gg.ctx
+	gg.idleMu.Unlock()
+	gg.launch(func() {
+		defer gg.finish()
+		defer gg.stats.completed.Add(1)
+		t.started = time.Now()
+		gg.stats.addRunning(RunningTask{ID: id, Name: t.name, Started: t.started})
+		defer gg.stats.removeRunning(id)
+		if t.handle != nil {
+			t.handle.begin(t.started)
+		}
+		if sem != nil {
+			defer func() { <-sem }()
+		}
+		var err error
+		if t.done != nil {
+			defer func() { t.done(err) }()
+		}
+		if t.handle != nil {
+			defer func() { t.handle.finish(err) }()
+		}
+		if gg.successRate != nil && t.name != "" {
+			defer func() { gg.successRate.record(t.name, err == nil, time.Now()) }()
+		}
 		defer func() {
 			if r := recover(); r != nil {
-				stack := debug.Stack()
-				gg.handler(r, stack)
-				err := recoveryToError(r)
-				
-				gg.errOnce.Do(func() {
-					gg.err = err
-				})
+				gg.event(id, t.name, EventPanic, fmt.Sprint(r))
+				err = gg.handlePanic(t, r)
+				gg.escalate(r)
 			}
 		}()
-		fn(gg.ctx)
-	}()
+		defer func() {
+			gg.stats.recordDuration(time.Since(t.started))
+		}()
+		gg.event(id, t.name, EventStart, "")
+		err = t.fn(ctx)
+		if gg.recordCancels {
+			err = gg.checkCancelled(ctx, t.name, t.started, err)
+		}
+		if err != nil {
+			gg.event(id, t.name, EventError, err.Error())
+			gg.recordError(t, err, false)
+			return
+		}
+		gg.event(id, t.name, EventFinish, "")
+	})
+}
+
+// finish marks a task as no longer running
+func (gg *GoroutineGroup) finish() {
+	gg.wg.Done()
+	gg.idleMu.Lock()
+	defer gg.idleMu.Unlock()
+	if gg.running--; gg.running == 0 {
+		close(gg.idle)
+		gg.stop(nil)
+		gg.ctx, gg.stop = nil, nil
+	}
+	if gg.progress != nil {
+		close(gg.progress)
+		gg.progress = nil
+	}
 }
 
+// progressed returns a channel closed when the next task finishes, and whether
+// there is more to wait for: some task is running and fewer than n have finished
+// in the batch. Tasks count as finished before they take idleMu in finish, so a
+// completion is either counted here or closes the returned channel.
+func (gg *GoroutineGroup) progressed(n int) (<-chan struct{}, bool) {
+	gg.idleMu.Lock()
+	defer gg.idleMu.Unlock()
+	if done, _ := gg.batchCounts(); gg.running == 0 || done >= n {
+		return nil, false
+	}
+	if gg.progress == nil {
+		gg.progress = make(chan struct{})
+	}
+	return gg.progress, true
+}
+
+// Done returns a channel that is closed once no tasks are running, for use in
+// select statements. Tasks started afterwards are tracked by a new channel, so Done
+// should be called again after submitting more work.
+func (gg *GoroutineGroup) Done() <-chan struct{} {
+	gg.idleMu.Lock()
+	defer gg.idleMu.Unlock()
+	if gg.running == 0 {
+		return closedChan
+	}
+	return gg.idle
+}
+
+var closedChan = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// SetLimit limits the number of tasks running concurrently to n. Further calls to
+// Go and GoErr block until a running task finishes. A negative n removes the limit.
+// The limit must not be changed while tasks are running. Tasks that submit to
+// their own group through FromContext can deadlock at the limit.
+func (gg *GoroutineGroup) SetLimit(n int) {
+	if n < 0 {
+		gg.sem, gg.sharedSem = nil, false
+		return
+	}
+	if !gg.sharedSem && len(gg.sem) != 0 {
+		panic(fmt.Errorf("goroutine_panic_helper: modify limit while %v tasks are still running", len(gg.sem)))
+	}
+	gg.sem, gg.sharedSem = make(chan struct{}, n), false
+}
+
+// WithLimit sets the concurrency limit at construction, see SetLimit
+func WithLimit(n int) Option {
+	return func(gg *GoroutineGroup) {
+		gg.SetLimit(n)
+	}
+}
+
+// handlePanic reports and records a panic of t and returns the error it was converted to
+func (gg *GoroutineGroup) handlePanic(t task, r any) error {
+	began := time.Now()
+	stack := debug.Stack()
+	gg.stats.stackCapture.Add(int64(time.Since(began)))
+	gg.stats.recordPanic(t, r, stack)
+	if gg.sampler == nil || gg.sampler.sample() {
+		began = time.Now()
+		if t.handler != nil {
+			t.handler(r, stack)
+		} else if gg.taskHandler != nil {
+			gg.taskHandler(t.name, r, stack)
+		} else {
+			gg.handler(r, stack)
+		}
+		gg.stats.handlerTime.Add(int64(time.Since(began)))
+	}
+	if gg.panicRate != nil {
+		gg.panicRate.record(time.Now())
+	}
+	began = time.Now()
+	err := gg.errFormat.toError(t.name, r, stack)
+	gg.stats.errorConversion.Add(int64(time.Since(began)))
+	gg.recordError(t, err, true)
+	return err
+}
+
+// escalate crashes the process for a handled panic r if WithCrashOnPanic or
+// RepanicIf ask for it. Every recover site calls it after handlePanic.
+func (gg *GoroutineGroup) escalate(r any) {
+	if gg.crash != nil {
+		gg.crash()
+	}
+	if gg.repanic != nil && gg.repanic(r) {
+		panic(r)
+	}
+}
+
+func (gg *GoroutineGroup) recordError(t task, err error, panicked bool) {
+	if !t.transient {
+		gg.errMu.Lock()
+		gg.errs = append(gg.errs, err)
+		gg.errMu.Unlock()
+	}
+	if gg.cancel != nil && (gg.cancelOnError || panicked && gg.cancelOnPanic) {
+		gg.cancel(err)
+	}
+}
+
+// Wait blocks until all tasks have finished and returns every failure recorded
+// by the group, in the order they happened: a single error as is, several joined
+// with errors.Join.
 func (gg *GoroutineGroup) Wait() error {
+	if gg.sentinel != nil {
+		gg.sentinel.waited.Store(true)
+	}
 	gg.wg.Wait()
-	return gg.err
+	err := gg.joinedErr()
+	if gg.cancel != nil {
+		gg.cancel(err)
+	}
+	if gg.leakCheck != nil {
+		gg.leakCheck.check()
+	}
+	if len(gg.flushers) > 0 {
+		return errors.Join(err, gg.flush())
+	}
+	return err
 }
 
-func DefaultPanicHandler(panic interface{}, stack []byte) {
-	fmt.Printf("Panic: %v\nStack: %s\n", panic, string(stack))
+// WaitChan calls Wait in the background and returns a channel that receives its
+// result once, so group completion can be handled in an event loop
+func (gg *GoroutineGroup) WaitChan() <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		ch <- gg.Wait()
+	}()
+	return ch
+}
+
+// WaitN blocks until n tasks of the group have finished, counted since the group
+// was created or last Reset, or no task is running, and returns the failures
+// recorded so far like Err. The remaining tasks keep running; cancel their context
+// if their results are not needed, and call Wait eventually.
+func (gg *GoroutineGroup) WaitN(n int) error {
+	for {
+		progress, more := gg.progressed(n)
+		if !more {
+			return gg.Err()
+		}
+		<-progress
+	}
+}
+
+// ErrWaitTimeout is returned by WaitContext and WaitTimeout when tasks are still
+// running as the wait gives up
+var ErrWaitTimeout = errors.New("wait timed out with tasks still running")
+
+// WaitContext is like Wait but gives up when ctx is done, returning an error that
+// wraps both ErrWaitTimeout and the cause of ctx. Running tasks are left to finish
+// on their own, and Wait can be called again later.
+func (gg *GoroutineGroup) WaitContext(ctx context.Context) error {
+	if gg.sentinel != nil {
+		gg.sentinel.waited.Store(true)
+	}
+	select {
+	case <-gg.Done():
+		return gg.Wait()
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrWaitTimeout, context.Cause(ctx))
+	}
+}
+
+// WaitWithProgress is like WaitContext and additionally calls report with the
+// number of finished and started tasks, counted since the group was created or
+// last Reset, as tasks finish, for progress bars. Completions that happen in quick
+// succession may be reported together. report is called a last time before Wait
+// returns.
+func (gg *GoroutineGroup) WaitWithProgress(ctx context.Context, report func(done, total int)) error {
+	if gg.sentinel != nil {
+		gg.sentinel.waited.Store(true)
+	}
+	for {
+		progress, more := gg.progressed(math.MaxInt)
+		report(gg.batchCounts())
+		if !more {
+			return gg.Wait()
+		}
+		select {
+		case <-progress:
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrWaitTimeout, context.Cause(ctx))
+		}
+	}
+}
+
+// WaitTimeout is like WaitContext with a context that times out after d
+func (gg *GoroutineGroup) WaitTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return gg.WaitContext(ctx)
+}
+
+// Reset starts a new batch on the group, so a long-lived configured group can be
+// reused after Wait returned. It clears the recorded failures, restarts the counts
+// of WaitN and WaitWithProgress, and gives CancelOnError groups a fresh context.
+// Snapshot statistics are kept. Reset must not be called while tasks are running,
+// and panics for groups from NewGoroutineGroupWithContext, whose context belongs
+// to the caller and cannot be renewed.
+func (gg *GoroutineGroup) Reset() {
+	if gg.cancel != nil && !gg.ownCancel {
+		panic("goroutine_panic_helper: reset of a group created by NewGoroutineGroupWithContext")
+	}
+	gg.idleMu.Lock()
+	defer gg.idleMu.Unlock()
+	if gg.running != 0 {
+		panic(fmt.Errorf("goroutine_panic_helper: reset while %v tasks are still running", gg.running))
+	}
+	if gg.ownCancel {
+		var ctx context.Context
+		ctx, gg.cancel = context.WithCancelCause(gg.parent)
+		gg.base = context.WithValue(ctx, groupContextKey{}, gg)
+	}
+	gg.batchStarted.Store(gg.stats.started.Load())
+	gg.batchCompleted.Store(gg.stats.completed.Load())
+	gg.batchPanicked.Store(gg.stats.panicked.Load())
+
+	gg.errMu.Lock()
+	gg.errs = nil
+	gg.errMu.Unlock()
+}
+
+// batchCounts returns the numbers of finished and started tasks since the group
+// was created or last Reset
+func (gg *GoroutineGroup) batchCounts() (done, total int) {
+	done = int(gg.stats.completed.Load() - gg.batchCompleted.Load())
+	total = int(gg.stats.started.Load() - gg.batchStarted.Load())
+	return done, total
+}
+
+// Err returns the failures recorded so far, like Wait but without waiting, so
+// supervisory code can poll a group while tasks are still running. It is safe to
+// call concurrently with running tasks.
+func (gg *GoroutineGroup) Err() error {
+	return gg.joinedErr()
+}
+
+// failures returns the recorded failures, one per failed task
+func (gg *GoroutineGroup) failures() []error {
+	gg.errMu.Lock()
+	defer gg.errMu.Unlock()
+	return slices.Clone(gg.errs)
+}
+
+func (gg *GoroutineGroup) joinedErr() error {
+	gg.errMu.Lock()
+	defer gg.errMu.Unlock()
+	if len(gg.errs) == 1 {
+		return gg.errs[0]
+	}
+	return errors.Join(gg.errs...)
 }
 
-func recoveryToError(recovery any) error {
-	switch value := recovery.(type) {
-	case string:
-		return fmt.Errorf("panic recovery: %s", value)
-	case error:
-		return fmt.Errorf("panic recovery: %w", value)
-	default:
-		return fmt.Errorf("panic recovery: %v", value)
+// WaitAllGroups waits on all groups concurrently and returns their errors joined.
+// If ctx is done first, the errors collected so far are returned joined with
+// ctx.Err(); groups still running are left to finish on their own.
+func WaitAllGroups(ctx context.Context, groups ...*GoroutineGroup) error {
+	type result struct {
+		index int
+		err   error
 	}
+	results := make(chan result, len(groups))
+	for i, group := range groups {
+		go func() {
+			results <- result{index: i, err: group.Wait()}
+		}()
+	}
+
+	errs := make([]error, len(groups))
+	for range groups {
+		select {
+		case r := <-results:
+			errs[r.index] = r.err
+		case <-ctx.Done():
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func DefaultPanicHandler(panic interface{}, stack []byte) {
+	fmt.Printf("Panic: %v\nStack: %s\n", panic, string(stack))
 }
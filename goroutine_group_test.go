@@ -2,6 +2,7 @@ package goroutine_panic_helper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync/atomic"
@@ -171,12 +172,15 @@ func TestGroup_MultipleGoroutines_MultiplePanics(t *testing.T) {
 		t.Error("Expected an error from Wait(), got nil")
 	}
 
-	// Only the first panic should be returned as error
-	if !strings.Contains(err.Error(), "panic 0") &&
-		!strings.Contains(err.Error(), "panic 1") &&
+	// Every panic should be returned as error
+	if !strings.Contains(err.Error(), "panic 0") ||
+		!strings.Contains(err.Error(), "panic 1") ||
 		!strings.Contains(err.Error(), "panic 2") {
 		t.Errorf("Unexpected error message: %v", err)
 	}
+	if errs := err.(interface{ Unwrap() []error }).Unwrap(); len(errs) != 3 {
+		t.Errorf("Expected 3 joined errors, got %d", len(errs))
+	}
 
 	if count := atomic.LoadInt32(&panicCount); count != 3 {
 		t.Errorf("Expected 3 panics to be caught, got %d", count)
@@ -208,3 +212,567 @@ func TestGroup_MultipleGoroutines_ContextCancellation(t *testing.T) {
 		t.Errorf("Expected no error from cancelled goroutines, got: %v", err)
 	}
 }
+
+func TestGroup_GoErr(t *testing.T) {
+	ctx := context.Background()
+	taskErr := errors.New("task failed")
+
+	group := NewGoroutineGroup(ctx, nil)
+	group.GoErr(func(ctx context.Context) error {
+		return nil
+	})
+	group.GoErr(func(ctx context.Context) error {
+		return taskErr
+	})
+
+	if err := group.Wait(); !errors.Is(err, taskErr) {
+		t.Errorf("Expected returned error from Wait(), got: %v", err)
+	}
+}
+
+func TestGroup_GoErr_Panic(t *testing.T) {
+	ctx := context.Background()
+	panicCaught := false
+
+	group := NewGoroutineGroup(ctx, func(r interface{}, stack []byte) {
+		panicCaught = true
+	})
+	group.GoErr(func(ctx context.Context) error {
+		panic("test panic")
+	})
+
+	err := group.Wait()
+	if err == nil || !strings.Contains(err.Error(), "test panic") {
+		t.Errorf("Expected panic error from Wait(), got: %v", err)
+	}
+	if !panicCaught {
+		t.Error("Panic was not caught by custom handler")
+	}
+}
+
+func TestGroup_WithContext_CancelOnPanic(t *testing.T) {
+	group, ctx := NewGoroutineGroupWithContext(context.Background(), func(r interface{}, stack []byte) {})
+	cancelled := false
+
+	group.Go(func(ctx context.Context) {
+		select {
+		case <-ctx.Done():
+			cancelled = true
+		case <-time.After(time.Second):
+			t.Error("Sibling was not cancelled after panic")
+		}
+	})
+	group.Go(func(ctx context.Context) {
+		panic("first panic")
+	})
+
+	err := group.Wait()
+	if !cancelled {
+		t.Error("Sibling did not observe cancellation")
+	}
+	if cause := context.Cause(ctx); cause != err {
+		t.Errorf("Expected context cause %v, got %v", err, cause)
+	}
+}
+
+func TestGroup_WithContext_CancelledAfterWait(t *testing.T) {
+	group, ctx := NewGoroutineGroupWithContext(context.Background(), nil)
+	group.Go(func(ctx context.Context) {})
+
+	if err := group.Wait(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("Expected derived context to be cancelled after Wait")
+	}
+}
+
+func TestGroup_CancelOnError(t *testing.T) {
+	taskErr := errors.New("task failed")
+	group := NewGoroutineGroup(context.Background(), nil, CancelOnError())
+	cancelled := false
+
+	group.Go(func(ctx context.Context) {
+		select {
+		case <-ctx.Done():
+			cancelled = context.Cause(ctx) == taskErr
+		case <-time.After(time.Second):
+			t.Error("Sibling was not cancelled after error")
+		}
+	})
+	group.GoErr(func(ctx context.Context) error {
+		return taskErr
+	})
+
+	if err := group.Wait(); err != taskErr {
+		t.Errorf("Expected %v, got: %v", taskErr, err)
+	}
+	if !cancelled {
+		t.Error("Sibling did not observe cancellation caused by the error")
+	}
+}
+
+func TestGroup_WithContext_ErrorDoesNotCancel(t *testing.T) {
+	group, ctx := NewGoroutineGroupWithContext(context.Background(), nil)
+	group.GoErr(func(ctx context.Context) error {
+		return errors.New("task failed")
+	})
+	group.Go(func(ctx context.Context) {
+		time.Sleep(20 * time.Millisecond)
+		if ctx.Err() != nil {
+			t.Error("Returned error cancelled the group without CancelOnError")
+		}
+	})
+	group.Wait()
+
+	if ctx.Err() == nil {
+		t.Error("Expected derived context to be cancelled after Wait")
+	}
+}
+
+func TestGroup_SetLimit(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.SetLimit(2)
+
+	running, maxRunning := int32(0), int32(0)
+	for i := 0; i < 10; i++ {
+		group.Go(func(ctx context.Context) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			if i%3 == 0 {
+				panic("limited panic")
+			}
+		})
+	}
+
+	if err := group.Wait(); err == nil {
+		t.Error("Expected an error from Wait(), got nil")
+	}
+	if max := atomic.LoadInt32(&maxRunning); max > 2 {
+		t.Errorf("Expected at most 2 concurrent tasks, got %d", max)
+	}
+	if snap := group.Snapshot(); snap.Completed != 10 {
+		t.Errorf("Expected 10 completed tasks, got %d", snap.Completed)
+	}
+}
+
+func TestGroup_TryGo(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.SetLimit(1)
+
+	release := make(chan struct{})
+	if !group.TryGo(func(ctx context.Context) {
+		<-release
+	}) {
+		t.Fatal("Expected TryGo to start a task below the limit")
+	}
+	if group.TryGo(func(ctx context.Context) {
+		t.Error("Task started above the limit")
+	}) {
+		t.Error("Expected TryGo to refuse a task at the limit")
+	}
+
+	close(release)
+	if err := group.Wait(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if !group.TryGo(func(ctx context.Context) {}) {
+		t.Error("Expected TryGo to start a task after the slot was freed")
+	}
+	group.Wait()
+}
+
+func TestGroup_TryGoTask(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.SetLimit(1)
+
+	release := make(chan struct{})
+	first, ok := group.TryGoTask(func(ctx context.Context) {
+		<-release
+	})
+	if !ok || first == nil {
+		t.Fatal("Expected TryGoTask to start a task below the limit")
+	}
+	if handle, ok := group.TryGoTask(func(ctx context.Context) {}); ok || handle != nil {
+		t.Error("Expected TryGoTask to refuse a task at the limit without a handle")
+	}
+	close(release)
+	if err := first.Await(context.Background()); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	group.Wait()
+}
+
+func TestGroup_GoNamed(t *testing.T) {
+	var handledTask string
+	group := NewGoroutineGroup(context.Background(), nil, WithTaskPanicHandler(func(task string, r interface{}, stack []byte) {
+		handledTask = task
+	}))
+	group.GoNamed("fetch-user", func(ctx context.Context) {
+		panic("not found")
+	})
+
+	err := group.Wait()
+	if handledTask != "fetch-user" {
+		t.Errorf("Expected handler to receive task name, got %q", handledTask)
+	}
+	if err == nil || err.Error() != `panic recovery in task "fetch-user": not found` {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Task() != "fetch-user" {
+		t.Errorf("Expected PanicError for task fetch-user, got: %v", err)
+	}
+}
+
+func TestGoWith(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil)
+	seen := make([]int32, 5)
+	for i := 0; i < 5; i++ {
+		GoWith(group, i, func(ctx context.Context, i int) {
+			atomic.AddInt32(&seen[i], 1)
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	for i, n := range seen {
+		if n != 1 {
+			t.Errorf("Argument %d was passed %d times", i, n)
+		}
+	}
+}
+
+func TestWaitAllGroups(t *testing.T) {
+	ctx := context.Background()
+	handler := func(r interface{}, stack []byte) {}
+
+	first := NewGoroutineGroup(ctx, handler)
+	first.Go(func(ctx context.Context) {
+		panic("first group panic")
+	})
+	second := NewGoroutineGroup(ctx, handler)
+	second.Go(func(ctx context.Context) {
+		time.Sleep(10 * time.Millisecond)
+	})
+	third := NewGoroutineGroup(ctx, handler)
+	third.Go(func(ctx context.Context) {
+		panic("third group panic")
+	})
+
+	err := WaitAllGroups(ctx, first, second, third)
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+	if !strings.Contains(err.Error(), "first group panic") || !strings.Contains(err.Error(), "third group panic") {
+		t.Errorf("Expected errors from both panicking groups, got: %v", err)
+	}
+}
+
+func TestWaitAllGroups_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := WaitAllGroups(ctx, group)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+}
+
+func TestGroup_WaitTimeout(t *testing.T) {
+	release := make(chan struct{})
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {
+		<-release
+		panic("late")
+	})
+
+	err := group.WaitTimeout(10 * time.Millisecond)
+	if !errors.Is(err, ErrWaitTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected timeout error, got: %v", err)
+	}
+
+	close(release)
+	if err := group.WaitTimeout(time.Second); !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected the panic once drained, got: %v", err)
+	}
+}
+
+func TestGroup_WaitContext_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := group.WaitContext(ctx); !errors.Is(err, ErrWaitTimeout) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation error, got: %v", err)
+	}
+}
+
+func TestGroup_Done(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil)
+	select {
+	case <-group.Done():
+	default:
+		t.Error("Expected Done to be closed for an empty group")
+	}
+
+	release := make(chan struct{})
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+	done := group.Done()
+	select {
+	case <-done:
+		t.Error("Done closed while a task is running")
+	default:
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Done was not closed after the task finished")
+	}
+	if err := group.Wait(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestGroup_WaitChan(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+
+	select {
+	case err := <-group.WaitChan():
+		if !errors.Is(err, ErrPanic) {
+			t.Errorf("Expected the panic error, got: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitChan did not deliver")
+	}
+}
+
+func TestGroup_Err(t *testing.T) {
+	release := make(chan struct{})
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		panic("early")
+	})
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for group.Err() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Err did not report the panic while a task was running")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if !errors.Is(group.Err(), ErrPanic) {
+		t.Errorf("Expected the panic error, got: %v", group.Err())
+	}
+
+	close(release)
+	if err := group.Wait(); !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected the panic error from Wait, got: %v", err)
+	}
+}
+
+func TestGroup_GoWithHandler(t *testing.T) {
+	var groupCalls, taskCalls atomic.Int32
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {
+		groupCalls.Add(1)
+	})
+	group.GoWithHandler(func(ctx context.Context) {
+		panic("tagged")
+	}, func(r interface{}, stack []byte) {
+		taskCalls.Add(1)
+	})
+	group.Go(func(ctx context.Context) {
+		panic("plain")
+	})
+
+	if err := group.Wait(); err == nil {
+		t.Error("Expected errors from both panics")
+	}
+	if taskCalls.Load() != 1 || groupCalls.Load() != 1 {
+		t.Errorf("Expected one call per handler, got task %d, group %d", taskCalls.Load(), groupCalls.Load())
+	}
+}
+
+func TestGroup_GoWithTimeout(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.GoWithTimeout(10*time.Millisecond, func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	group.GoWithTimeout(time.Second, func(ctx context.Context) {})
+
+	err := group.Wait()
+	if !errors.Is(err, ErrTaskTimeout) {
+		t.Fatalf("Expected a timeout error, got: %v", err)
+	}
+	if err.Error() != "task timed out after 10ms" {
+		t.Errorf("Expected only the timed out task to fail, got: %v", err)
+	}
+}
+
+func TestGroup_WaitN(t *testing.T) {
+	release := make(chan struct{})
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {})
+	group.Go(func(ctx context.Context) {
+		panic("fast")
+	})
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+
+	if err := group.WaitN(2); !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected the panic among the first 2, got: %v", err)
+	}
+	if completed := group.Snapshot().Completed; completed < 2 {
+		t.Errorf("Expected at least 2 completed tasks, got %d", completed)
+	}
+
+	close(release)
+	if err := group.WaitN(10); !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected WaitN to return once the group is idle, got: %v", err)
+	}
+	group.Wait()
+}
+
+func TestGroup_Reset(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		panic("first batch")
+	})
+	if err := group.Wait(); err == nil {
+		t.Fatal("Expected an error from the first batch")
+	}
+
+	group.Reset()
+	group.Go(func(ctx context.Context) {})
+	if err := group.Wait(); err != nil {
+		t.Errorf("Expected no error after Reset, got: %v", err)
+	}
+}
+
+func TestGroup_Reset_Running(t *testing.T) {
+	release := make(chan struct{})
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+	defer func() {
+		close(release)
+		group.Wait()
+		if recover() == nil {
+			t.Error("Expected Reset to panic while tasks are running")
+		}
+	}()
+	group.Reset()
+}
+
+func TestGroup_WaitWithProgress(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil)
+	for i := range 5 {
+		group.Go(func(ctx context.Context) {
+			time.Sleep(time.Duration(i) * time.Millisecond)
+		})
+	}
+
+	var reports [][2]int
+	err := group.WaitWithProgress(context.Background(), func(done, total int) {
+		reports = append(reports, [2]int{done, total})
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if last := reports[len(reports)-1]; last != [2]int{5, 5} {
+		t.Errorf("Expected final report 5/5, got %v", reports)
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i][0] < reports[i-1][0] {
+			t.Errorf("Progress went backwards: %v", reports)
+		}
+	}
+}
+
+func TestGroup_WaitWithProgress_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := group.WaitWithProgress(ctx, func(done, total int) {})
+	if !errors.Is(err, ErrWaitTimeout) {
+		t.Errorf("Expected a timeout error, got: %v", err)
+	}
+}
+
+func TestGroup_Reset_Batch(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {}, CancelOnError())
+	group.Go(func(ctx context.Context) {})
+	group.Go(func(ctx context.Context) {
+		panic("first batch")
+	})
+	group.Wait()
+	group.Reset()
+
+	release := make(chan struct{})
+	group.Go(func(ctx context.Context) {
+		if ctx.Err() != nil {
+			t.Error("Expected a fresh context after Reset")
+		}
+	})
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+	if err := group.WaitN(1); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if done, total := group.batchCounts(); done != 1 || total != 2 {
+		t.Errorf("Expected counts of the new batch 1/2, got %d/%d", done, total)
+	}
+	close(release)
+	group.Wait()
+}
+
+func TestGroup_Reset_WithContext(t *testing.T) {
+	group, _ := NewGoroutineGroupWithContext(context.Background(), nil)
+	group.Wait()
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Reset to panic for a caller-owned context")
+		}
+	}()
+	group.Reset()
+}
@@ -0,0 +1,32 @@
+package goroutine_panic_helper
+
+// Launcher starts the goroutines running a group's tasks. The default starts each
+// one with a go statement; integrations can use worker pools, custom schedulers,
+// or test harnesses instead. Launch must eventually run fn exactly once. Recovery,
+// tracking, and Wait work the same whichever Launcher is used.
+type Launcher interface {
+	Launch(fn func())
+}
+
+// LauncherFunc adapts a function to the Launcher interface
+type LauncherFunc func(fn func())
+
+func (f LauncherFunc) Launch(fn func()) {
+	f(fn)
+}
+
+// WithLauncher starts the group's tasks through l
+func WithLauncher(l Launcher) Option {
+	return func(gg *GoroutineGroup) {
+		gg.launcher = l
+	}
+}
+
+// launch runs fn through the group's Launcher, or on a new goroutine
+func (gg *GoroutineGroup) launch(fn func()) {
+	if gg.launcher != nil {
+		gg.launcher.Launch(fn)
+		return
+	}
+	go fn()
+}
@@ -0,0 +1,44 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGroup_WithLauncher(t *testing.T) {
+	var launched atomic.Int32
+	launcher := LauncherFunc(func(fn func()) {
+		launched.Add(1)
+		go fn()
+	})
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {}, WithLauncher(launcher))
+	group.Go(func(ctx context.Context) {})
+	group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+
+	if err := group.Wait(); !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected the panic to be recovered, got: %v", err)
+	}
+	if launched.Load() != 2 {
+		t.Errorf("Expected 2 launches, got %d", launched.Load())
+	}
+}
+
+func TestGroup_WithLauncher_Inline(t *testing.T) {
+	var order []string
+	group := NewGoroutineGroup(context.Background(), nil, WithLauncher(LauncherFunc(func(fn func()) {
+		fn()
+	})))
+	group.Go(func(ctx context.Context) {
+		order = append(order, "task")
+	})
+	order = append(order, "after Go")
+	group.Wait()
+
+	if len(order) != 2 || order[0] != "task" {
+		t.Errorf("Expected the task to run inline, got %v", order)
+	}
+}
@@ -0,0 +1,102 @@
+package goroutine_panic_helper
+
+import (
+	"bytes"
+	"runtime"
+	"runtime/debug"
+	"runtime/pprof"
+	"sync/atomic"
+)
+
+// LeakReport describes a group that was garbage collected without Wait being called
+type LeakReport struct {
+	// Started is the number of tasks submitted to the group
+	Started int64
+	// Stack is the stack trace of the NewGoroutineGroup call that created the group
+	Stack []byte
+}
+
+// WithLeakSentinel arms a finalizer that calls hook if the group is garbage
+// collected before Wait was called. Panics from tasks of such a group are never
+// returned to anyone, so this catches "forgot to Wait" bugs in long-running services.
+//
+// Tasks still running hold a reference to their group, so a report always means
+// the tasks finished but nobody observed the result.
+func WithLeakSentinel(hook func(LeakReport)) Option {
+	return func(gg *GoroutineGroup) {
+		if hook != nil {
+			gg.sentinel = &leakSentinel{hook: hook}
+		}
+	}
+}
+
+var strictWaitHook atomic.Pointer[func(LeakReport)]
+
+// SetStrictWait enables strict mode: every group constructed afterwards is armed as
+// if created with WithLeakSentinel(hook), unless it configures its own sentinel.
+// Intended for TestMain or service startup. Passing nil disables strict mode.
+func SetStrictWait(hook func(LeakReport)) {
+	if hook == nil {
+		strictWaitHook.Store(nil)
+		return
+	}
+	strictWaitHook.Store(&hook)
+}
+
+// leakSentinel is kept apart from the group so the finalizer still runs when the
+// group is part of a reference cycle.
+type leakSentinel struct {
+	hook    func(LeakReport)
+	stack   []byte
+	started atomic.Int64
+	waited  atomic.Bool
+}
+
+func (s *leakSentinel) arm() {
+	s.stack = debug.Stack()
+	runtime.SetFinalizer(s, func(s *leakSentinel) {
+		if !s.waited.Load() {
+			s.hook(LeakReport{Started: s.started.Load(), Stack: s.stack})
+		}
+	})
+}
+
+// GoroutineLeak describes a suspected leak detected when Wait returned
+type GoroutineLeak struct {
+	// Baseline is runtime.NumGoroutine() when the group was created
+	Baseline int
+	// Current is runtime.NumGoroutine() after all tasks finished
+	Current int
+	// Profile is the goroutine profile in pprof debug=1 text format
+	Profile []byte
+}
+
+// WithGoroutineLeakCheck compares runtime.NumGoroutine() after Wait against the
+// count when the group was created, and calls report if it grew by more than
+// threshold. This catches tasks that spawned unsupervised goroutines. The count is
+// process-wide, so unrelated goroutines started meanwhile are included.
+func WithGoroutineLeakCheck(threshold int, report func(GoroutineLeak)) Option {
+	return func(gg *GoroutineGroup) {
+		gg.leakCheck = &goroutineLeakCheck{
+			threshold: threshold,
+			report:    report,
+			baseline:  runtime.NumGoroutine(),
+		}
+	}
+}
+
+type goroutineLeakCheck struct {
+	threshold int
+	report    func(GoroutineLeak)
+	baseline  int
+}
+
+func (c *goroutineLeakCheck) check() {
+	current := runtime.NumGoroutine()
+	if current-c.baseline <= c.threshold {
+		return
+	}
+	var profile bytes.Buffer
+	pprof.Lookup("goroutine").WriteTo(&profile, 1)
+	c.report(GoroutineLeak{Baseline: c.baseline, Current: current, Profile: profile.Bytes()})
+}
@@ -0,0 +1,119 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func waitForFinalizers(reported chan LeakReport) (LeakReport, bool) {
+	deadline := time.After(time.Second)
+	for {
+		runtime.GC()
+		select {
+		case report := <-reported:
+			return report, true
+		case <-deadline:
+			return LeakReport{}, false
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestGroup_LeakSentinel_NoWait(t *testing.T) {
+	reported := make(chan LeakReport, 1)
+
+	func() {
+		group := NewGoroutineGroup(context.Background(), nil, WithLeakSentinel(func(r LeakReport) {
+			reported <- r
+		}))
+		group.Go(func(ctx context.Context) {})
+		group.Go(func(ctx context.Context) {})
+		group.wg.Wait()
+	}()
+
+	report, ok := waitForFinalizers(reported)
+	if !ok {
+		t.Fatal("Expected leak report for group without Wait")
+	}
+	if report.Started != 2 {
+		t.Errorf("Expected 2 started tasks, got %d", report.Started)
+	}
+	if len(report.Stack) == 0 {
+		t.Error("Expected creation stack in leak report")
+	}
+}
+
+func TestGroup_LeakSentinel_Waited(t *testing.T) {
+	reported := make(chan LeakReport, 1)
+
+	func() {
+		group := NewGoroutineGroup(context.Background(), nil, WithLeakSentinel(func(r LeakReport) {
+			reported <- r
+		}))
+		group.Go(func(ctx context.Context) {})
+		group.Wait()
+	}()
+
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+	}
+	select {
+	case <-reported:
+		t.Error("Unexpected leak report for waited group")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSetStrictWait(t *testing.T) {
+	reported := make(chan LeakReport, 1)
+	SetStrictWait(func(r LeakReport) {
+		select {
+		case reported <- r:
+		default:
+		}
+	})
+	defer SetStrictWait(nil)
+
+	func() {
+		group := NewGoroutineGroup(context.Background(), nil)
+		group.Go(func(ctx context.Context) {})
+		group.wg.Wait()
+	}()
+
+	if _, ok := waitForFinalizers(reported); !ok {
+		t.Fatal("Expected strict mode to report group without Wait")
+	}
+}
+
+func TestGroup_GoroutineLeakCheck(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	var leak *GoroutineLeak
+	group := NewGoroutineGroup(context.Background(), nil, WithGoroutineLeakCheck(0, func(l GoroutineLeak) {
+		leak = &l
+	}))
+	group.Go(func(ctx context.Context) {
+		go func() {
+			<-release
+		}()
+	})
+	group.Wait()
+
+	if leak == nil {
+		t.Fatal("Expected suspected leak to be reported")
+	}
+	if leak.Current <= leak.Baseline || len(leak.Profile) == 0 {
+		t.Errorf("Unexpected leak report: baseline=%d current=%d", leak.Baseline, leak.Current)
+	}
+}
+
+func TestGroup_GoroutineLeakCheck_NoLeak(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil, WithGoroutineLeakCheck(10, func(l GoroutineLeak) {
+		t.Errorf("Unexpected leak report: baseline=%d current=%d", l.Baseline, l.Current)
+	}))
+	group.Go(func(ctx context.Context) {})
+	group.Wait()
+}
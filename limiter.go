@@ -0,0 +1,24 @@
+package goroutine_panic_helper
+
+// Limiter is a concurrency limit shared by several groups, so independent
+// subsystems that hit the same downstream, such as one database, are throttled
+// together rather than per group
+type Limiter struct {
+	sem chan struct{}
+}
+
+// NewLimiter creates a Limiter allowing n tasks to run at once across all groups
+// using it; n must not be negative
+func NewLimiter(n int) *Limiter {
+	return &Limiter{sem: make(chan struct{}, n)}
+}
+
+// WithLimiter makes the group take its concurrency limit from l. Go and GoErr
+// block while l is exhausted by the tasks of any group using it, and TryGo fails.
+// SetLimit replaces the shared limit with one of the group's own.
+func WithLimiter(l *Limiter) Option {
+	return func(gg *GoroutineGroup) {
+		gg.sem = l.sem
+		gg.sharedSem = true
+	}
+}
@@ -0,0 +1,58 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithLimiter(t *testing.T) {
+	limiter := NewLimiter(2)
+	var running, peak atomic.Int32
+	task := func(ctx context.Context) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+	}
+
+	a := NewGoroutineGroup(context.Background(), nil, WithLimiter(limiter))
+	b := NewGoroutineGroup(context.Background(), nil, WithLimiter(limiter))
+	for i := 0; i < 5; i++ {
+		a.Go(task)
+		b.Go(task)
+	}
+	a.Wait()
+	b.Wait()
+
+	if p := peak.Load(); p != 2 {
+		t.Errorf("Expected at most 2 tasks across both groups, peak was %d", p)
+	}
+}
+
+func TestWithLimiter_TryGo(t *testing.T) {
+	limiter := NewLimiter(1)
+	a := NewGoroutineGroup(context.Background(), nil, WithLimiter(limiter))
+	b := NewGoroutineGroup(context.Background(), nil, WithLimiter(limiter))
+
+	release := make(chan struct{})
+	a.Go(func(ctx context.Context) {
+		<-release
+	})
+	if b.TryGo(func(ctx context.Context) {}) {
+		t.Error("Expected TryGo to fail while the shared limiter is exhausted")
+	}
+	b.SetLimit(1)
+	if !b.TryGo(func(ctx context.Context) {}) {
+		t.Error("Expected TryGo to succeed with a limit of the group's own")
+	}
+	close(release)
+	a.Wait()
+	b.Wait()
+}
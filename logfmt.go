@@ -0,0 +1,72 @@
+package goroutine_panic_helper
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const logfmtStackFrames = 5
+
+// LogfmtHandler returns a PanicHandler that writes each panic to w as a single
+// logfmt line, for log pipelines that are logfmt-based rather than JSON. The
+// stack is condensed to its innermost frames. PanicValue codes and fields are
+// written as keys of their own, fields prefixed with "field.". Writes are
+// serialized, one per panic.
+func LogfmtHandler(w io.Writer) PanicHandler {
+	var mu sync.Mutex
+	return func(r interface{}, stack []byte) {
+		var b strings.Builder
+		b.WriteString("time=")
+		b.WriteString(time.Now().UTC().Format(time.RFC3339Nano))
+		b.WriteString(" level=error msg=")
+		b.WriteString(logfmtValue("goroutine panic recovered"))
+		b.WriteString(" panic=")
+		b.WriteString(logfmtValue(fmt.Sprint(r)))
+		if v, ok := r.(*PanicValue); ok {
+			if v.Code != "" {
+				b.WriteString(" code=")
+				b.WriteString(logfmtValue(v.Code))
+			}
+			for _, key := range slices.Sorted(maps.Keys(v.Fields)) {
+				if key == "" {
+					continue
+				}
+				b.WriteString(" field." + logfmtKey(key) + "=")
+				b.WriteString(logfmtValue(fmt.Sprint(v.Fields[key])))
+			}
+		}
+		if head := stackHead(stack, logfmtStackFrames); head != "" {
+			b.WriteString(" stack=")
+			b.WriteString(logfmtValue(head))
+		}
+		b.WriteByte('\n')
+
+		mu.Lock()
+		defer mu.Unlock()
+		io.WriteString(w, b.String())
+	}
+}
+
+// logfmtKey replaces the characters a logfmt key cannot contain with underscores
+func logfmtKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+func logfmtValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\\\n\t") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,93 @@
+package goroutine_panic_helper
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLogfmtHandler(t *testing.T) {
+	var buf bytes.Buffer
+	group := NewGoroutineGroup(context.Background(), LogfmtHandler(&buf))
+	group.Go(func(ctx context.Context) {
+		panic("bad \"input\"\nline two")
+	})
+	group.Wait()
+
+	line := buf.String()
+	if strings.Count(line, "\n") != 1 || !strings.HasSuffix(line, "\n") {
+		t.Fatalf("Expected a single line, got: %q", line)
+	}
+	for _, want := range []string{
+		"level=error",
+		`msg="goroutine panic recovered"`,
+		`panic="bad \"input\"\nline two"`,
+		`stack="github.com/onurburak9/goroutine-panic-helper.TestLogfmtHandler.func1 `,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected %q in %q", want, line)
+		}
+	}
+}
+
+func TestLogfmtValue(t *testing.T) {
+	tests := map[string]string{
+		"plain": "plain",
+		"":      `""`,
+		"a b":   `"a b"`,
+		"k=v":   `"k=v"`,
+	}
+	for in, want := range tests {
+		if got := logfmtValue(in); got != want {
+			t.Errorf("logfmtValue(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestLogfmtHandler_PanicValue(t *testing.T) {
+	var buf bytes.Buffer
+	group := NewGoroutineGroup(context.Background(), LogfmtHandler(&buf))
+	group.Go(func(ctx context.Context) {
+		panic(&PanicValue{Code: "E_QUOTA", Message: "quota exceeded", Fields: map[string]any{"tenant": "acme", "limit": 10}})
+	})
+	group.Wait()
+
+	if want := `panic="E_QUOTA: quota exceeded" code=E_QUOTA field.limit=10 field.tenant=acme`; !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected %q in %q", want, buf.String())
+	}
+}
+
+func TestLogfmtHandler_PanicValueKeys(t *testing.T) {
+	var buf bytes.Buffer
+	group := NewGoroutineGroup(context.Background(), LogfmtHandler(&buf))
+	group.Go(func(ctx context.Context) {
+		panic(&PanicValue{Message: "bad", Fields: map[string]any{
+			"panic":      "shadow",
+			"user id":    7,
+			`a="b"`:      "x",
+			"":           "dropped",
+			"line\nnext": 1,
+		}})
+	})
+	group.Wait()
+
+	line := buf.String()
+	if strings.Count(line, "\n") != 1 {
+		t.Fatalf("Expected a single line, got: %q", line)
+	}
+	for _, want := range []string{
+		`panic=bad `,
+		`field.a__b_=x`,
+		`field.line_next=1`,
+		`field.panic=shadow`,
+		`field.user_id=7`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected %q in %q", want, line)
+		}
+	}
+	if strings.Contains(line, "dropped") {
+		t.Errorf("Expected the empty key to be skipped, got %q", line)
+	}
+}
@@ -0,0 +1,73 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrMailboxClosed is returned by Mailbox operations after Close
+var ErrMailboxClosed = errors.New("mailbox closed")
+
+// Mailbox is a typed, bounded queue meant to be shared among group tasks. Unlike a
+// raw channel, sending after Close returns an error instead of panicking, and every
+// blocking operation gives up when the caller's context is cancelled.
+type Mailbox[T any] struct {
+	ch        chan T
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMailbox creates a Mailbox buffering up to size values
+func NewMailbox[T any](size int) *Mailbox[T] {
+	return &Mailbox[T]{ch: make(chan T, size), done: make(chan struct{})}
+}
+
+// Send blocks until v is queued, ctx is done, or the mailbox is closed
+func (m *Mailbox[T]) Send(ctx context.Context, v T) error {
+	select {
+	case <-m.done:
+		return ErrMailboxClosed
+	default:
+	}
+	select {
+	case m.ch <- v:
+		return nil
+	case <-m.done:
+		return ErrMailboxClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Receive blocks until a value is available or ctx is done. Values queued before
+// Close are still delivered; afterwards Receive returns ErrMailboxClosed.
+func (m *Mailbox[T]) Receive(ctx context.Context) (T, error) {
+	select {
+	case v := <-m.ch:
+		return v, nil
+	default:
+	}
+	var zero T
+	select {
+	case v := <-m.ch:
+		return v, nil
+	case <-m.done:
+		select {
+		case v := <-m.ch:
+			return v, nil
+		default:
+			return zero, ErrMailboxClosed
+		}
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Close closes the mailbox, unblocking all waiting senders and receivers. It is
+// safe to call more than once.
+func (m *Mailbox[T]) Close() {
+	m.closeOnce.Do(func() {
+		close(m.done)
+	})
+}
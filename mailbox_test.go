@@ -0,0 +1,67 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMailbox(t *testing.T) {
+	ctx := context.Background()
+	mailbox := NewMailbox[int](2)
+	group := NewGoroutineGroup(ctx, nil)
+
+	group.Go(func(ctx context.Context) {
+		defer mailbox.Close()
+		for i := 0; i < 5; i++ {
+			if err := mailbox.Send(ctx, i); err != nil {
+				t.Errorf("Unexpected send error: %v", err)
+			}
+		}
+	})
+
+	sum := 0
+	group.Go(func(ctx context.Context) {
+		for {
+			v, err := mailbox.Receive(ctx)
+			if errors.Is(err, ErrMailboxClosed) {
+				return
+			}
+			sum += v
+		}
+	})
+
+	if err := group.Wait(); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if sum != 10 {
+		t.Errorf("Expected all values to be received before close, got sum %d", sum)
+	}
+}
+
+func TestMailbox_Closed(t *testing.T) {
+	mailbox := NewMailbox[string](1)
+	mailbox.Close()
+	mailbox.Close()
+
+	if err := mailbox.Send(context.Background(), "late"); !errors.Is(err, ErrMailboxClosed) {
+		t.Errorf("Expected ErrMailboxClosed, got: %v", err)
+	}
+	if _, err := mailbox.Receive(context.Background()); !errors.Is(err, ErrMailboxClosed) {
+		t.Errorf("Expected ErrMailboxClosed, got: %v", err)
+	}
+}
+
+func TestMailbox_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	mailbox := NewMailbox[int](0)
+	if _, err := mailbox.Receive(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+	if err := mailbox.Send(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+}
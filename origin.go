@@ -0,0 +1,77 @@
+package goroutine_panic_helper
+
+import (
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// Origin classifies which code a panic was raised from
+type Origin int
+
+const (
+	OriginUnknown Origin = iota
+	// OriginMainModule is code of the main module, "our bug"
+	OriginMainModule
+	// OriginDependency is code of another module, "library bug"
+	OriginDependency
+	// OriginStandardLibrary is code of the standard library
+	OriginStandardLibrary
+)
+
+func (o Origin) String() string {
+	switch o {
+	case OriginMainModule:
+		return "main"
+	case OriginDependency:
+		return "dependency"
+	case OriginStandardLibrary:
+		return "stdlib"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the origin as its name
+func (o Origin) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+var mainModulePath = sync.OnceValue(func() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		return info.Main.Path
+	}
+	return ""
+})
+
+// ClassifyOrigin classifies the innermost non-runtime frame of a panic stack, as
+// passed to a PanicHandler, by whether it belongs to the main module, a dependency,
+// or the standard library. Handlers can use it to route alerts differently.
+func ClassifyOrigin(stack []byte) Origin {
+	frames := panicFrames(stack, 1)
+	if len(frames) == 0 {
+		return OriginUnknown
+	}
+	return classifyFunction(frames[0].function, mainModulePath())
+}
+
+func classifyFunction(function, mainModule string) Origin {
+	pkg := function
+	slash := strings.LastIndex(pkg, "/")
+	if dot := strings.Index(pkg[slash+1:], "."); dot >= 0 {
+		pkg = pkg[:slash+1+dot]
+	}
+
+	switch {
+	// package main is always the program's own code, even when built outside of
+	// a module or run with go run
+	case pkg == "main":
+		return OriginMainModule
+	case mainModule != "" && (pkg == mainModule || strings.HasPrefix(pkg, mainModule+"/")):
+		return OriginMainModule
+	case !strings.Contains(strings.SplitN(pkg, "/", 2)[0], "."):
+		return OriginStandardLibrary
+	default:
+		return OriginDependency
+	}
+}
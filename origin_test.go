@@ -0,0 +1,62 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestClassifyOrigin(t *testing.T) {
+	var origin Origin
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {
+		origin = ClassifyOrigin(stack)
+	})
+	group.Go(func(ctx context.Context) {
+		panicInParse()
+	})
+	group.Wait()
+
+	if origin != OriginMainModule {
+		t.Errorf("Expected main module origin, got %v", origin)
+	}
+	if ClassifyOrigin([]byte("not a stack")) != OriginUnknown {
+		t.Error("Expected unknown origin for unparsable stack")
+	}
+}
+
+func TestClassifyFunction(t *testing.T) {
+	const mainModule = "example.com/app"
+	tests := map[string]Origin{
+		"example.com/app.main":                      OriginMainModule,
+		"example.com/app/internal/parse.(*P).Parse": OriginMainModule,
+		"example.com/application.Run":               OriginDependency,
+		"github.com/lib/pq.(*conn).query":           OriginDependency,
+		"encoding/json.(*decodeState).object":       OriginStandardLibrary,
+		"strings.Repeat":                            OriginStandardLibrary,
+		"main.handler":                              OriginMainModule,
+		"main.(*server).ServeHTTP":                  OriginMainModule,
+		"main.main.func1":                           OriginMainModule,
+	}
+	for function, want := range tests {
+		if got := classifyFunction(function, mainModule); got != want {
+			t.Errorf("classifyFunction(%q) = %v, want %v", function, got, want)
+		}
+	}
+}
+
+func TestSnapshot_PanicOrigin(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		_ = strings.Repeat("x", -1)
+	})
+	group.Wait()
+
+	data, err := json.Marshal(group.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"origin":"stdlib"`) {
+		t.Errorf("Expected stdlib origin in snapshot, got %s", data)
+	}
+}
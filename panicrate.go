@@ -0,0 +1,98 @@
+package goroutine_panic_helper
+
+import (
+	"sync"
+	"time"
+)
+
+// AlertLevel is the severity of a panic rate alert
+type AlertLevel int
+
+const (
+	AlertNone AlertLevel = iota
+	AlertWarning
+	AlertCritical
+)
+
+func (l AlertLevel) String() string {
+	switch l {
+	case AlertWarning:
+		return "warning"
+	case AlertCritical:
+		return "critical"
+	default:
+		return "none"
+	}
+}
+
+// WithPanicRateAlert tracks the number of panics in the group over the last minute
+// and calls alert each time the rate rises to the warning or critical threshold.
+// A threshold of zero disables that level. Alerts fire again after the rate has
+// dropped below a level and crosses it anew.
+func WithPanicRateAlert(warning, critical int, alert func(level AlertLevel, perMinute int)) Option {
+	return func(gg *GoroutineGroup) {
+		gg.panicRate = &panicRate{warning: warning, critical: critical, alert: alert}
+	}
+}
+
+// PanicRate returns the number of panics recovered during the last minute. It is
+// only tracked when the group was created with WithPanicRateAlert.
+func (gg *GoroutineGroup) PanicRate() int {
+	if gg.panicRate == nil {
+		return 0
+	}
+	return gg.panicRate.perMinute(time.Now())
+}
+
+// panicRate counts panics in one-second buckets over a sliding minute.
+type panicRate struct {
+	warning, critical int
+	alert             func(AlertLevel, int)
+
+	mu      sync.Mutex
+	buckets [60]int
+	seconds [60]int64
+	level   AlertLevel
+}
+
+func (p *panicRate) record(now time.Time) {
+	p.mu.Lock()
+	sec := now.Unix()
+	i := sec % 60
+	if p.seconds[i] != sec {
+		p.seconds[i] = sec
+		p.buckets[i] = 0
+	}
+	p.buckets[i]++
+	rate := p.sumLocked(sec)
+
+	level := AlertNone
+	if p.critical > 0 && rate >= p.critical {
+		level = AlertCritical
+	} else if p.warning > 0 && rate >= p.warning {
+		level = AlertWarning
+	}
+	raised := level > p.level
+	p.level = level
+	p.mu.Unlock()
+
+	if raised && p.alert != nil {
+		p.alert(level, rate)
+	}
+}
+
+func (p *panicRate) perMinute(now time.Time) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sumLocked(now.Unix())
+}
+
+func (p *panicRate) sumLocked(sec int64) int {
+	total := 0
+	for i, count := range p.buckets {
+		if sec-p.seconds[i] < 60 {
+			total += count
+		}
+	}
+	return total
+}
@@ -0,0 +1,50 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroup_PanicRateAlert(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		alerts []AlertLevel
+	)
+	alert := func(level AlertLevel, perMinute int) {
+		mu.Lock()
+		defer mu.Unlock()
+		alerts = append(alerts, level)
+	}
+
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {},
+		WithPanicRateAlert(2, 4, alert))
+	for i := 0; i < 5; i++ {
+		group.Go(func(ctx context.Context) {
+			panic("rate panic")
+		})
+	}
+	group.Wait()
+
+	if rate := group.PanicRate(); rate != 5 {
+		t.Errorf("Expected panic rate 5, got %d", rate)
+	}
+	if len(alerts) != 2 || alerts[0] == alerts[1] {
+		t.Errorf("Expected one warning and one critical alert, got %v", alerts)
+	}
+}
+
+func TestPanicRate_Window(t *testing.T) {
+	p := &panicRate{}
+	start := time.Unix(1000, 0)
+	p.record(start)
+	p.record(start.Add(30 * time.Second))
+
+	if rate := p.perMinute(start.Add(45 * time.Second)); rate != 2 {
+		t.Errorf("Expected 2 panics within the minute, got %d", rate)
+	}
+	if rate := p.perMinute(start.Add(75 * time.Second)); rate != 1 {
+		t.Errorf("Expected 1 panic within the minute, got %d", rate)
+	}
+}
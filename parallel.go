@@ -0,0 +1,151 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// Map calls fn for every item concurrently on a new group configured by opts, and
+// returns the results in input order. Items whose fn failed, by returning an error
+// or by panicking, have the zero value. Panics are named after the item index, e.g.
+// task "item 3". Use WithLimit to bound concurrency and WithCheckpoint to record
+// progress.
+func Map[T, R any](ctx context.Context, items []T, fn func(context.Context, T) (R, error), opts ...Option) ([]R, error) {
+	results := make([]R, len(items))
+	group := NewGoroutineGroup(ctx, nil, opts...)
+	checkpoint := group.newCheckpointer()
+	for i, item := range items {
+		group.submit(task{
+			name: itemName(i),
+			fn: func(ctx context.Context) error {
+				r, err := fn(ctx, item)
+				if err != nil {
+					return err
+				}
+				results[i] = r
+				return nil
+			},
+			done: checkpoint.done(i),
+		})
+	}
+	err := group.Wait()
+	checkpoint.flush()
+	return results, err
+}
+
+// MapMerge is like Map for items that may repeat, such as inputs containing
+// retries. fn is called for every item, and the results of items with the same key
+// are combined with merge in input order, so the outcome is deterministic. It
+// returns one result per key, ordered by the key's first occurrence; failed items
+// are left out of the merge.
+func MapMerge[T any, K comparable, R any](ctx context.Context, items []T, key func(T) K, fn func(context.Context, T) (R, error), merge func(a, b R) R, opts ...Option) ([]R, error) {
+	results := make([]R, len(items))
+	ok := make([]bool, len(items))
+	group := NewGoroutineGroup(ctx, nil, opts...)
+	checkpoint := group.newCheckpointer()
+	for i, item := range items {
+		group.submit(task{
+			name: itemName(i),
+			fn: func(ctx context.Context) error {
+				r, err := fn(ctx, item)
+				if err != nil {
+					return err
+				}
+				results[i], ok[i] = r, true
+				return nil
+			},
+			done: checkpoint.done(i),
+		})
+	}
+	err := group.Wait()
+	checkpoint.flush()
+
+	merged := make([]R, 0, len(items))
+	index := make(map[K]int)
+	for i, item := range items {
+		if !ok[i] {
+			continue
+		}
+		k := key(item)
+		if j, seen := index[k]; seen {
+			merged[j] = merge(merged[j], results[i])
+			continue
+		}
+		index[k] = len(merged)
+		merged = append(merged, results[i])
+	}
+	return merged, err
+}
+
+func itemName(i int) string {
+	return fmt.Sprintf("item %d", i)
+}
+
+// ForEach calls fn for every item concurrently on a new group configured by opts,
+// for side-effect-only work. It returns the failures of all items joined, see Map.
+func ForEach[T any](ctx context.Context, items []T, fn func(context.Context, T) error, opts ...Option) error {
+	group := NewGoroutineGroup(ctx, nil, opts...)
+	checkpoint := group.newCheckpointer()
+	for i, item := range items {
+		group.submit(task{
+			name: itemName(i),
+			fn: func(ctx context.Context) error {
+				return fn(ctx, item)
+			},
+			done: checkpoint.done(i),
+		})
+	}
+	err := group.Wait()
+	checkpoint.flush()
+	return err
+}
+
+// ForEachSeq is like ForEach for an iterator. Values are consumed as they are
+// produced; with WithLimit, iteration pauses while the group is at its limit. It
+// stops consuming once ctx is done. A panic in the iterator itself is handled and
+// returned like a task panic, named "iterator".
+func ForEachSeq[T any](ctx context.Context, seq iter.Seq[T], fn func(context.Context, T) error, opts ...Option) error {
+	return ForEachSeq2(ctx, func(yield func(int, T) bool) {
+		i := 0
+		for v := range seq {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}, func(ctx context.Context, _ int, v T) error {
+		return fn(ctx, v)
+	}, opts...)
+}
+
+// ForEachSeq2 is like ForEachSeq for key-value iterators
+func ForEachSeq2[K, V any](ctx context.Context, seq iter.Seq2[K, V], fn func(context.Context, K, V) error, opts ...Option) error {
+	group := NewGoroutineGroup(ctx, nil, opts...)
+	checkpoint := group.newCheckpointer()
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				group.handlePanic(task{name: "iterator"}, r)
+				group.escalate(r)
+			}
+		}()
+		i := 0
+		for k, v := range seq {
+			if ctx.Err() != nil {
+				return
+			}
+			group.submit(task{
+				name: itemName(i),
+				fn: func(ctx context.Context) error {
+					return fn(ctx, k, v)
+				},
+				done: checkpoint.done(i),
+			})
+			i++
+		}
+	}()
+	err := group.Wait()
+	checkpoint.flush()
+	return err
+}
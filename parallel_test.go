@@ -0,0 +1,181 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMap(t *testing.T) {
+	items := []string{"1", "2", "3", "4", "5", "6"}
+	running, maxRunning := int32(0), int32(0)
+
+	results, err := Map(context.Background(), items, func(ctx context.Context, s string) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return strconv.Atoi(s)
+	}, WithLimit(2))
+
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	for i, r := range results {
+		if r != i+1 {
+			t.Errorf("Expected results in input order, got %v", results)
+			break
+		}
+	}
+	if max := atomic.LoadInt32(&maxRunning); max > 2 {
+		t.Errorf("Expected at most 2 concurrent calls, got %d", max)
+	}
+}
+
+func TestMap_Failures(t *testing.T) {
+	items := []string{"1", "x", "3", "panic"}
+	results, err := Map(context.Background(), items, func(ctx context.Context, s string) (int, error) {
+		if s == "panic" {
+			panic("bad item")
+		}
+		return strconv.Atoi(s)
+	}, WithTaskPanicHandler(func(task string, r interface{}, stack []byte) {}))
+
+	if results[0] != 1 || results[1] != 0 || results[2] != 3 || results[3] != 0 {
+		t.Errorf("Unexpected results: %v", results)
+	}
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Errorf("Expected parse error, got: %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), `panic recovery in task "item 3": bad item`) {
+		t.Errorf("Expected named panic error, got: %v", err)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	items := []int{1, 2, 3, 4}
+	sum := int32(0)
+	failure := errors.New("odd item")
+
+	err := ForEach(context.Background(), items, func(ctx context.Context, n int) error {
+		atomic.AddInt32(&sum, int32(n))
+		if n == 3 {
+			return failure
+		}
+		if n == 4 {
+			panic("item four")
+		}
+		return nil
+	}, WithLimit(2), WithTaskPanicHandler(func(task string, r interface{}, stack []byte) {}))
+
+	if atomic.LoadInt32(&sum) != 10 {
+		t.Errorf("Expected every item to be processed, sum %d", sum)
+	}
+	if !errors.Is(err, failure) || !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected returned error and panic, got: %v", err)
+	}
+}
+
+func TestForEachSeq(t *testing.T) {
+	seq := func(yield func(int) bool) {
+		for i := 1; i <= 5; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	sum := int32(0)
+	err := ForEachSeq(context.Background(), seq, func(ctx context.Context, n int) error {
+		atomic.AddInt32(&sum, int32(n))
+		return nil
+	}, WithLimit(2))
+
+	if err != nil || atomic.LoadInt32(&sum) != 15 {
+		t.Errorf("Expected sum 15 without error, got %d, %v", sum, err)
+	}
+}
+
+func TestForEachSeq2_IteratorPanic(t *testing.T) {
+	seq := func(yield func(string, int) bool) {
+		if !yield("a", 1) {
+			return
+		}
+		panic("iterator broke")
+	}
+	processed := int32(0)
+	err := ForEachSeq2(context.Background(), seq, func(ctx context.Context, k string, v int) error {
+		atomic.AddInt32(&processed, 1)
+		return nil
+	}, WithTaskPanicHandler(func(task string, r interface{}, stack []byte) {}))
+
+	if atomic.LoadInt32(&processed) != 1 {
+		t.Errorf("Expected the yielded item to be processed, got %d", processed)
+	}
+	if err == nil || !strings.Contains(err.Error(), `task "iterator": iterator broke`) {
+		t.Errorf("Expected iterator panic error, got: %v", err)
+	}
+}
+
+func TestForEachSeq_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	seq := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	count := int32(0)
+	ForEachSeq(ctx, seq, func(ctx context.Context, n int) error {
+		if atomic.AddInt32(&count, 1) == 3 {
+			cancel()
+		}
+		return nil
+	}, WithLimit(1))
+
+	if n := atomic.LoadInt32(&count); n > 4 {
+		t.Errorf("Expected iteration to stop after cancellation, processed %d", n)
+	}
+}
+
+func TestMapMerge(t *testing.T) {
+	type attempt struct {
+		id    string
+		value int
+	}
+	items := []attempt{{"a", 1}, {"b", 2}, {"a", 3}, {"c", 0}, {"b", 4}, {"a", 5}}
+	results, err := MapMerge(context.Background(), items, func(a attempt) string {
+		return a.id
+	}, func(ctx context.Context, a attempt) ([]int, error) {
+		if a.value == 0 {
+			return nil, errors.New("attempt failed")
+		}
+		return []int{a.value}, nil
+	}, func(a, b []int) []int {
+		return append(a, b...)
+	})
+
+	if err == nil {
+		t.Error("Expected the failed attempt to be reported")
+	}
+	want := [][]int{{1, 3, 5}, {2, 4}}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, results)
+	}
+	for i := range want {
+		if !slices.Equal(results[i], want[i]) {
+			t.Errorf("Expected %v, got %v", want, results)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrEmptyRace is returned by Race when it is given no functions
+var ErrEmptyRace = errors.New("race without functions")
+
+// errRaceWon is the cancellation cause seen by the losers of a Race
+var errRaceWon = errors.New("another task won the race")
+
+// Race calls every fn concurrently on a new group configured by opts and returns
+// the result of the first one to succeed, for hedged requests and multi-region
+// lookups. The context of the others is cancelled once there is a winner, and Race
+// returns after they have returned. Panics of losers are still passed to the
+// group's handler. If no fn succeeds, Race returns the failures of all of them
+// joined; panics are named after the index of the fn, see Map. Without any fn it
+// returns ErrEmptyRace.
+func Race[R any](ctx context.Context, fns []func(context.Context) (R, error), opts ...Option) (R, error) {
+	if len(fns) == 0 {
+		var zero R
+		return zero, ErrEmptyRace
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var (
+		once   sync.Once
+		winner R
+		won    bool
+	)
+	group := NewGoroutineGroup(ctx, nil, opts...)
+	for i, fn := range fns {
+		group.submit(task{
+			name: itemName(i),
+			fn: func(ctx context.Context) error {
+				r, err := fn(ctx)
+				if err != nil {
+					return err
+				}
+				once.Do(func() {
+					winner, won = r, true
+					cancel(errRaceWon)
+				})
+				return nil
+			},
+		})
+	}
+	err := group.Wait()
+	if won {
+		return winner, nil
+	}
+	return winner, err
+}
@@ -0,0 +1,58 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRace(t *testing.T) {
+	var cancelled atomic.Bool
+	winner, err := Race(context.Background(), []func(context.Context) (string, error){
+		func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			cancelled.Store(errors.Is(context.Cause(ctx), errRaceWon))
+			return "", ctx.Err()
+		},
+		func(ctx context.Context) (string, error) {
+			time.Sleep(5 * time.Millisecond)
+			return "fast", nil
+		},
+		func(ctx context.Context) (string, error) {
+			panic("loser panicked")
+		},
+	}, WithTaskPanicHandler(func(task string, r interface{}, stack []byte) {}))
+
+	if err != nil || winner != "fast" {
+		t.Errorf("Expected the fast result, got %q, %v", winner, err)
+	}
+	if !cancelled.Load() {
+		t.Error("Expected the slow task to be cancelled by the winner")
+	}
+}
+
+func TestRace_AllFail(t *testing.T) {
+	_, err := Race(context.Background(), []func(context.Context) (int, error){
+		func(ctx context.Context) (int, error) {
+			return 0, errors.New("region down")
+		},
+		func(ctx context.Context) (int, error) {
+			panic("boom")
+		},
+	}, WithTaskPanicHandler(func(task string, r interface{}, stack []byte) {}))
+
+	if err == nil || !errors.Is(err, ErrPanic) {
+		t.Fatalf("Expected all failures, got: %v", err)
+	}
+	if err.Error() != "region down\npanic recovery in task \"item 1\": boom" && err.Error() != "panic recovery in task \"item 1\": boom\nregion down" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestRace_Empty(t *testing.T) {
+	if _, err := Race[int](context.Background(), nil); !errors.Is(err, ErrEmptyRace) {
+		t.Errorf("Expected ErrEmptyRace, got: %v", err)
+	}
+}
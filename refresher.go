@@ -0,0 +1,159 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// refreshBackoff is the delay before retrying a failed fetch; it doubles with
+// every consecutive failure, up to the TTL
+const refreshBackoff = 100 * time.Millisecond
+
+// Refresher caches a value computed by a panic-protected function for a TTL, the
+// usual pattern for configuration and feature flags. Run refreshes it periodically
+// in the background; Get serves the cached value and refreshes it itself once it
+// is stale. Failed fetches are retried with exponential backoff rather than on
+// every Get, so a failing backend is not hammered.
+type Refresher[T any] struct {
+	fetch   func(context.Context) (T, error)
+	ttl     time.Duration
+	handler PanicHandler
+
+	mu      sync.Mutex
+	value   T
+	loaded  bool
+	err     error
+	fetched time.Time
+	// failures counts consecutive failed fetches, the last one at failed
+	failures int
+	failed   time.Time
+	// refreshing is closed when the running refresh finishes, nil when idle
+	refreshing chan struct{}
+}
+
+// NewRefresher creates a Refresher that keeps values returned by fetch for ttl.
+// Panics in fetch are reported to handler; a nil handler uses DefaultPanicHandler.
+// Like time.NewTicker it panics if ttl is not positive.
+func NewRefresher[T any](fetch func(context.Context) (T, error), ttl time.Duration, handler PanicHandler) *Refresher[T] {
+	if ttl <= 0 {
+		panic("goroutine_panic_helper: non-positive refresher TTL")
+	}
+	if handler == nil {
+		handler = DefaultPanicHandler
+	}
+	return &Refresher[T]{fetch: fetch, ttl: ttl, handler: handler}
+}
+
+// Run refreshes the value every TTL, or sooner after a failed fetch following the
+// backoff, until ctx is done. Run it on its own goroutine or as a group task.
+func (r *Refresher[T]) Run(ctx context.Context) {
+	for {
+		r.mu.Lock()
+		done := r.refresh(ctx)
+		r.mu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return
+		}
+
+		r.mu.Lock()
+		next := r.ttl
+		if r.failures > 0 {
+			next = r.backoff()
+		}
+		r.mu.Unlock()
+		timer := time.NewTimer(next)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// Get returns the cached value, starting a background refresh if it is older than
+// the TTL. A stale value is still served while the refresh runs, and kept if the
+// refresh fails. Before the first successful fetch Get waits for a refresh, or for
+// ctx to be done, and returns the error of the last failed fetch. After a failed
+// fetch no new one is started until the backoff has passed. The refresh runs with
+// ctx's values but is not cancelled along with it.
+func (r *Refresher[T]) Get(ctx context.Context) (T, error) {
+	r.mu.Lock()
+	if r.loaded && time.Since(r.fetched) < r.ttl {
+		defer r.mu.Unlock()
+		return r.value, nil
+	}
+	if r.refreshing == nil && r.failures > 0 && time.Since(r.failed) < r.backoff() {
+		defer r.mu.Unlock()
+		if r.loaded {
+			return r.value, nil
+		}
+		return r.value, r.err
+	}
+	done := r.refresh(ctx)
+	if r.loaded {
+		defer r.mu.Unlock()
+		return r.value, nil
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.loaded {
+		return r.value, nil
+	}
+	return r.value, r.err
+}
+
+// backoff returns the delay after the last failed fetch before the next one;
+// r.mu must be held
+func (r *Refresher[T]) backoff() time.Duration {
+	d := refreshBackoff << min(r.failures-1, 30)
+	if d > r.ttl {
+		return r.ttl
+	}
+	return d
+}
+
+// refresh starts a refresh unless one is running and returns the channel closed
+// when it finishes; r.mu must be held
+func (r *Refresher[T]) refresh(ctx context.Context) chan struct{} {
+	if r.refreshing != nil {
+		return r.refreshing
+	}
+	done := make(chan struct{})
+	r.refreshing = done
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer close(done)
+		var value T
+		var err error
+		if perr := runProtected(r.handler, func() {
+			value, err = r.fetch(ctx)
+		}); perr != nil {
+			err = perr
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.refreshing = nil
+		r.err = err
+		if err != nil {
+			r.failures++
+			r.failed = time.Now()
+			return
+		}
+		r.value, r.loaded, r.fetched = value, true, time.Now()
+		r.failures = 0
+	}()
+	return done
+}
@@ -0,0 +1,136 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefresher_Get(t *testing.T) {
+	var calls atomic.Int32
+	r := NewRefresher(func(ctx context.Context) (int32, error) {
+		return calls.Add(1), nil
+	}, 20*time.Millisecond, nil)
+
+	for range 3 {
+		v, err := r.Get(context.Background())
+		if err != nil || v != 1 {
+			t.Fatalf("Expected cached 1, got %v, %v", v, err)
+		}
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if v, _ := r.Get(context.Background()); v != 1 {
+		t.Errorf("Expected the stale value while refreshing, got %v", v)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if v, _ := r.Get(context.Background()); v == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Value was not refreshed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRefresher_Panic(t *testing.T) {
+	var panicked atomic.Bool
+	var fail atomic.Bool
+	fail.Store(true)
+	r := NewRefresher(func(ctx context.Context) (string, error) {
+		if fail.Load() {
+			panic("fetch failed")
+		}
+		return "ok", nil
+	}, time.Hour, func(r interface{}, stack []byte) {
+		panicked.Store(true)
+	})
+
+	if _, err := r.Get(context.Background()); !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected the panic as error, got: %v", err)
+	}
+	if !panicked.Load() {
+		t.Error("Expected the handler to be called")
+	}
+
+	fail.Store(false)
+	if _, err := r.Get(context.Background()); !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected the last error during the backoff, got: %v", err)
+	}
+	time.Sleep(refreshBackoff)
+	if v, err := r.Get(context.Background()); err != nil || v != "ok" {
+		t.Errorf("Expected a retry after the backoff, got %q, %v", v, err)
+	}
+}
+
+func TestRefresher_Backoff(t *testing.T) {
+	var calls atomic.Int32
+	r := NewRefresher(func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		return 0, errors.New("backend down")
+	}, time.Hour, nil)
+
+	for range 10 {
+		r.Get(context.Background())
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected a single fetch during the backoff, got %d", n)
+	}
+}
+
+func TestRefresher_Run(t *testing.T) {
+	var calls atomic.Int32
+	r := NewRefresher(func(ctx context.Context) (int32, error) {
+		return calls.Add(1), nil
+	}, 5*time.Millisecond, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		r.Run(ctx)
+		close(stopped)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for calls.Load() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("Run did not refresh periodically")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-stopped
+	if v, err := r.Get(context.Background()); err != nil || v < 3 {
+		t.Errorf("Expected a value refreshed by Run, got %v, %v", v, err)
+	}
+}
+
+func TestRefresher_GetCancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	r := NewRefresher(func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	}, time.Hour, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := r.Get(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got: %v", err)
+	}
+}
+
+func TestRefresher_InvalidTTL(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected NewRefresher to panic for a zero TTL")
+		}
+	}()
+	NewRefresher(func(ctx context.Context) (int, error) {
+		return 0, nil
+	}, 0, nil)
+}
@@ -0,0 +1,50 @@
+package goroutine_panic_helper
+
+import (
+	"os"
+	"runtime"
+)
+
+// DevModeEnv is the environment variable enabling development mode. When set to
+// "1", groups created without RepanicIf re-raise runtime errors, so local runs
+// keep fail-fast behavior while production recovers.
+const DevModeEnv = "GOROUTINE_PANIC_HELPER_DEV"
+
+func devMode() bool {
+	return os.Getenv(DevModeEnv) == "1"
+}
+
+// RepanicIf re-raises panics for which match returns true after they have been
+// reported and recorded, crashing the process. Note that some runtime failures,
+// such as concurrent map writes, are fatal errors that are never recoverable.
+func RepanicIf(match func(r any) bool) Option {
+	return func(gg *GoroutineGroup) {
+		gg.repanic = match
+	}
+}
+
+// Repanic re-raises every panic after it has been reported and recorded, for
+// services that prefer a loud crash to a panic converted into an error
+func Repanic() Option {
+	return RepanicIf(func(any) bool { return true })
+}
+
+// WithCrashOnPanic terminates the process with exitCode once a panic has been
+// reported and recorded, after flushing the group's Flushers, for deployments
+// that rely on restarts, such as Kubernetes. Deferred functions of other
+// goroutines do not run.
+func WithCrashOnPanic(exitCode int) Option {
+	return func(gg *GoroutineGroup) {
+		gg.crash = func() {
+			gg.flush()
+			os.Exit(exitCode)
+		}
+	}
+}
+
+// IsRuntimeError reports whether r is a runtime.Error, such as a nil pointer
+// dereference, an out of range index, or an assignment to a nil map
+func IsRuntimeError(r any) bool {
+	_, ok := r.(runtime.Error)
+	return ok
+}
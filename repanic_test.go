@@ -0,0 +1,144 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsRuntimeError(t *testing.T) {
+	var r any
+	func() {
+		defer func() { r = recover() }()
+		var p *struct{ n int }
+		_ = p.n
+	}()
+	if !IsRuntimeError(r) {
+		t.Errorf("Expected nil dereference to be a runtime error, got %T", r)
+	}
+	if IsRuntimeError("plain panic") {
+		t.Error("Expected string panic not to be a runtime error")
+	}
+}
+
+// TestRepanicIf_Crash runs itself in a subprocess, in development mode, and
+// expects the process to crash after the handler ran.
+func TestRepanicIf_Crash(t *testing.T) {
+	if os.Getenv("REPANIC_CHILD") == "1" {
+		group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {
+			os.Stderr.WriteString("handler ran\n")
+		})
+		group.Go(func(ctx context.Context) {
+			panic("plain panic")
+		})
+		group.Wait()
+		group.Go(func(ctx context.Context) {
+			var m map[string]int
+			m["key"] = 1
+		})
+		group.Wait()
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRepanicIf_Crash$")
+	cmd.Env = append(os.Environ(), "REPANIC_CHILD=1", DevModeEnv+"=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected the process to crash, output:\n%s", out)
+	}
+	if strings.Count(string(out), "handler ran") != 2 {
+		t.Errorf("Expected handler to run for both panics, output:\n%s", out)
+	}
+	if !strings.Contains(string(out), "assignment to entry in nil map") {
+		t.Errorf("Expected runtime error to be re-raised, output:\n%s", out)
+	}
+}
+
+// TestRepanic_Crash runs itself in a subprocess and expects a plain panic to crash
+// the process after the handler ran.
+func TestRepanic_Crash(t *testing.T) {
+	if os.Getenv("REPANIC_CHILD") == "1" {
+		group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {
+			os.Stderr.WriteString("handler ran\n")
+		}, Repanic())
+		group.Go(func(ctx context.Context) {
+			panic("plain panic")
+		})
+		group.Wait()
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRepanic_Crash$")
+	cmd.Env = append(os.Environ(), "REPANIC_CHILD=1")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected the process to crash, output:\n%s", out)
+	}
+	if !strings.Contains(string(out), "handler ran") || !strings.Contains(string(out), "panic: plain panic") {
+		t.Errorf("Expected handler to run before the crash, output:\n%s", out)
+	}
+}
+
+type stderrFlusher struct{}
+
+func (stderrFlusher) Flush(ctx context.Context) error {
+	_, err := os.Stderr.WriteString("flushed\n")
+	return err
+}
+
+// TestWithCrashOnPanic runs itself in a subprocess and expects it to exit with the
+// configured code after the handler ran and the flusher was flushed.
+func TestWithCrashOnPanic(t *testing.T) {
+	if os.Getenv("REPANIC_CHILD") == "1" {
+		group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {
+			os.Stderr.WriteString("handler ran\n")
+		}, WithCrashOnPanic(3), WithFlusher(stderrFlusher{}, time.Second))
+		group.Go(func(ctx context.Context) {
+			panic("plain panic")
+		})
+		group.Wait()
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestWithCrashOnPanic$")
+	cmd.Env = append(os.Environ(), "REPANIC_CHILD=1")
+	out, err := cmd.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("Expected exit code 3, got %v, output:\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "handler ran\nflushed\n") {
+		t.Errorf("Expected handler and flush before exit, output:\n%s", out)
+	}
+}
+
+// TestWithCrashOnPanic_RecoverSites checks that panics recovered outside of tasks,
+// in Wrap callbacks and ForEachSeq iterators, crash the process as well
+func TestWithCrashOnPanic_RecoverSites(t *testing.T) {
+	switch os.Getenv("REPANIC_SITE") {
+	case "wrap":
+		group := NewGoroutineGroup(context.Background(), nil, WithCrashOnPanic(4))
+		group.Wrap("callback", func() { panic("callback panic") })()
+		return
+	case "iterator":
+		seq := func(yield func(int) bool) { panic("iterator panic") }
+		ForEachSeq(context.Background(), seq, func(ctx context.Context, v int) error {
+			return nil
+		}, WithCrashOnPanic(4))
+		return
+	}
+
+	for _, site := range []string{"wrap", "iterator"} {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestWithCrashOnPanic_RecoverSites$")
+		cmd.Env = append(os.Environ(), "REPANIC_SITE="+site)
+		out, err := cmd.CombinedOutput()
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 4 {
+			t.Errorf("%s: expected exit code 4, got %v, output:\n%s", site, err, out)
+		}
+	}
+}
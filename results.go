@@ -0,0 +1,95 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"sync"
+)
+
+// ResultGroup is a GoroutineGroup whose tasks produce values. Tasks are
+// panic-protected the same way and Wait collects their results.
+type ResultGroup[T any] struct {
+	group *GoroutineGroup
+
+	mu      sync.Mutex
+	results []T
+	ok      []bool
+	stream  chan Result[T]
+}
+
+// NewResultGroup creates a ResultGroup; the arguments are as for NewGoroutineGroup
+func NewResultGroup[T any](ctx context.Context, handler PanicHandler, opts ...Option) *ResultGroup[T] {
+	return &ResultGroup[T]{group: NewGoroutineGroup(ctx, handler, opts...)}
+}
+
+// Go runs fn in a new goroutine. Its value is collected if it returns a nil error.
+func (rg *ResultGroup[T]) Go(fn func(context.Context) (T, error)) {
+	rg.mu.Lock()
+	i := len(rg.results)
+	var zero T
+	rg.results = append(rg.results, zero)
+	rg.ok = append(rg.ok, false)
+	stream := rg.stream
+	rg.mu.Unlock()
+
+	t := task{fn: func(ctx context.Context) error {
+		v, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+		rg.mu.Lock()
+		rg.results[i] = v
+		rg.ok[i] = true
+		rg.mu.Unlock()
+		return nil
+	}}
+	if stream != nil {
+		t.done = func(err error) {
+			rg.mu.Lock()
+			r := Result[T]{Value: rg.results[i], Err: err}
+			rg.mu.Unlock()
+			stream <- r
+		}
+	}
+	rg.group.submit(t)
+}
+
+// Results switches the group to streaming mode and returns a channel delivering
+// each task's result, or its returned or panic-derived error, as soon as the task
+// finishes. It must be called before the first Go. The channel is unbuffered: a
+// finished task waits, holding its slot under SetLimit, until its result is
+// received, so results must be consumed on a different goroutine than the one
+// calling Wait. The channel is closed when Wait returns; tasks started afterwards
+// are not streamed unless Results is called again.
+func (rg *ResultGroup[T]) Results() <-chan Result[T] {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+	if rg.stream == nil {
+		rg.stream = make(chan Result[T])
+	}
+	return rg.stream
+}
+
+// SetLimit limits the number of tasks running concurrently, see GoroutineGroup.SetLimit
+func (rg *ResultGroup[T]) SetLimit(n int) {
+	rg.group.SetLimit(n)
+}
+
+// Wait blocks until all tasks have finished and returns the values of the tasks
+// that succeeded, in submission order, along with the group's error
+func (rg *ResultGroup[T]) Wait() ([]T, error) {
+	err := rg.group.Wait()
+
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+	if rg.stream != nil {
+		close(rg.stream)
+		rg.stream = nil
+	}
+	results := make([]T, 0, len(rg.results))
+	for i, v := range rg.results {
+		if rg.ok[i] {
+			results = append(results, v)
+		}
+	}
+	return results, err
+}
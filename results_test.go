@@ -0,0 +1,111 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResultGroup(t *testing.T) {
+	group := NewResultGroup[int](context.Background(), nil)
+	for i := 0; i < 5; i++ {
+		group.Go(func(ctx context.Context) (int, error) {
+			time.Sleep(time.Duration(5-i) * time.Millisecond)
+			return i * 10, nil
+		})
+	}
+
+	results, err := group.Wait()
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	want := []int{0, 10, 20, 30, 40}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, results)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, results)
+			break
+		}
+	}
+}
+
+func TestResultGroup_Failures(t *testing.T) {
+	taskErr := errors.New("task failed")
+	group := NewResultGroup[string](context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) (string, error) {
+		return "ok", nil
+	})
+	group.Go(func(ctx context.Context) (string, error) {
+		return "", taskErr
+	})
+	group.Go(func(ctx context.Context) (string, error) {
+		panic("result panic")
+	})
+
+	results, err := group.Wait()
+	if len(results) != 1 || results[0] != "ok" {
+		t.Errorf("Expected only the successful result, got %v", results)
+	}
+	if !errors.Is(err, taskErr) || !strings.Contains(err.Error(), "result panic") {
+		t.Errorf("Expected both failures in error, got: %v", err)
+	}
+}
+
+func TestResultGroup_Results(t *testing.T) {
+	group := NewResultGroup[int](context.Background(), func(r interface{}, stack []byte) {})
+	results := group.Results()
+
+	go func() {
+		for i := 0; i < 4; i++ {
+			group.Go(func(ctx context.Context) (int, error) {
+				if i == 3 {
+					panic("streamed panic")
+				}
+				return i, nil
+			})
+		}
+		group.Wait()
+	}()
+
+	sum, failed := 0, 0
+	for r := range results {
+		if r.Err != nil {
+			if !errors.Is(r.Err, ErrPanic) {
+				t.Errorf("Expected panic error, got: %v", r.Err)
+			}
+			failed++
+			continue
+		}
+		sum += r.Value
+	}
+	if sum != 3 || failed != 1 {
+		t.Errorf("Expected sum 3 and 1 failure, got sum %d and %d failures", sum, failed)
+	}
+}
+
+func TestResultGroup_Results_WaitTwice(t *testing.T) {
+	group := NewResultGroup[int](context.Background(), nil)
+	results := group.Results()
+	group.Go(func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	go func() {
+		for range results {
+		}
+	}()
+	group.Wait()
+
+	if _, err := group.Wait(); err != nil {
+		t.Errorf("Unexpected error from second Wait: %v", err)
+	}
+	group.Go(func(ctx context.Context) (int, error) {
+		return 2, nil
+	})
+	if values, err := group.Wait(); err != nil || len(values) != 2 {
+		t.Errorf("Expected both values after Go following Wait, got %v, %v", values, err)
+	}
+}
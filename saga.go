@@ -0,0 +1,90 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Saga runs steps concurrently, each with a compensation. If any step panics or
+// returns an error, the compensations of the steps that completed successfully
+// are run in reverse order of completion. Compensations are panic-protected too.
+type Saga struct {
+	handler PanicHandler
+	steps   []sagaStep
+}
+
+type sagaStep struct {
+	action     func(context.Context) error
+	compensate func(context.Context) error
+}
+
+// NewSaga creates a Saga reporting panics from steps and compensations to handler.
+// A nil handler uses DefaultPanicHandler.
+func NewSaga(handler PanicHandler) *Saga {
+	return &Saga{handler: handler}
+}
+
+// Step registers an action and the compensation that undoes it. compensate may be
+// nil for steps that need no undo.
+func (s *Saga) Step(action, compensate func(context.Context) error) {
+	s.steps = append(s.steps, sagaStep{action: action, compensate: compensate})
+}
+
+// Run executes all steps concurrently and returns the errors of failed steps and
+// compensations joined. Remaining steps see their context cancelled after the
+// first failure. Compensations run with a context that is not cancelled along
+// with ctx, so they complete even when the saga was aborted.
+func (s *Saga) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		completed []int
+		stepErrs  []error
+	)
+	group := NewGoroutineGroup(ctx, s.handler)
+	for i, step := range s.steps {
+		group.Go(func(ctx context.Context) {
+			failed := true
+			defer func() {
+				if failed {
+					cancel()
+				}
+			}()
+			if err := step.action(ctx); err != nil {
+				mu.Lock()
+				stepErrs = append(stepErrs, err)
+				mu.Unlock()
+				return
+			}
+			failed = false
+			mu.Lock()
+			completed = append(completed, i)
+			mu.Unlock()
+		})
+	}
+
+	waitErr := group.Wait()
+	err := errors.Join(append(stepErrs, waitErr)...)
+	if err == nil {
+		return nil
+	}
+
+	compensateCtx := context.WithoutCancel(ctx)
+	errs := []error{err}
+	for i := len(completed) - 1; i >= 0; i-- {
+		compensate := s.steps[completed[i]].compensate
+		if compensate == nil {
+			continue
+		}
+		var compErr error
+		group := NewGoroutineGroup(compensateCtx, s.handler)
+		group.Go(func(ctx context.Context) {
+			compErr = compensate(ctx)
+		})
+		errs = append(errs, compErr, group.Wait())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,90 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSaga_Success(t *testing.T) {
+	compensated := false
+	saga := NewSaga(nil)
+	for i := 0; i < 3; i++ {
+		saga.Step(func(ctx context.Context) error {
+			return nil
+		}, func(ctx context.Context) error {
+			compensated = true
+			return nil
+		})
+	}
+
+	if err := saga.Run(context.Background()); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if compensated {
+		t.Error("Compensation ran for a successful saga")
+	}
+}
+
+func TestSaga_CompensatesInReverseOrder(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		completed   []string
+		compensated []string
+	)
+	step := func(name string) (func(context.Context) error, func(context.Context) error) {
+		return func(ctx context.Context) error {
+				mu.Lock()
+				completed = append(completed, name)
+				mu.Unlock()
+				return nil
+			}, func(ctx context.Context) error {
+				if ctx.Err() != nil {
+					t.Error("Compensation context was cancelled")
+				}
+				compensated = append(compensated, name)
+				return nil
+			}
+	}
+
+	saga := NewSaga(func(r interface{}, stack []byte) {})
+	saga.Step(step("a"))
+	saga.Step(step("b"))
+	saga.Step(func(ctx context.Context) error {
+		panic("step failed")
+	}, func(ctx context.Context) error {
+		t.Error("Compensation ran for the failed step")
+		return nil
+	})
+
+	err := saga.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "step failed") {
+		t.Fatalf("Expected step panic in error, got: %v", err)
+	}
+	if len(compensated) != 2 || compensated[0] != completed[1] || compensated[1] != completed[0] {
+		t.Errorf("Expected compensations in reverse of %v, got %v", completed, compensated)
+	}
+}
+
+func TestSaga_CompensationPanic(t *testing.T) {
+	stepErr := errors.New("step error")
+	saga := NewSaga(func(r interface{}, stack []byte) {})
+	saga.Step(func(ctx context.Context) error {
+		return nil
+	}, func(ctx context.Context) error {
+		panic("compensation panic")
+	})
+	saga.Step(func(ctx context.Context) error {
+		return stepErr
+	}, nil)
+
+	err := saga.Run(context.Background())
+	if !errors.Is(err, stepErr) {
+		t.Errorf("Expected step error, got: %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "compensation panic") {
+		t.Errorf("Expected compensation panic in error, got: %v", err)
+	}
+}
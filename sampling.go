@@ -0,0 +1,55 @@
+package goroutine_panic_helper
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// WithHandlerSampling passes only a fraction rate (0 to 1) of panics to the panic
+// handler, protecting external reporting systems from panic storms. The first
+// panic from each distinct call path is always passed on. Sampling does not affect
+// the error returned from Wait.
+func WithHandlerSampling(rate float64) Option {
+	return func(gg *GoroutineGroup) {
+		if rate >= 1 {
+			gg.sampler = nil
+			return
+		}
+		gg.sampler = &panicSampler{rate: rate, seen: make(map[uint64]struct{})}
+	}
+}
+
+type panicSampler struct {
+	rate float64
+	mu   sync.Mutex
+	seen map[uint64]struct{}
+}
+
+// sample reports whether the panic currently unwinding the calling goroutine
+// should be passed to the handler.
+func (s *panicSampler) sample() bool {
+	fingerprint := panicFingerprint()
+
+	s.mu.Lock()
+	_, seen := s.seen[fingerprint]
+	s.seen[fingerprint] = struct{}{}
+	s.mu.Unlock()
+
+	return !seen || rand.Float64() < s.rate
+}
+
+// panicFingerprint hashes the call path of the calling goroutine. It is stable for
+// panics raised from the same code path.
+func panicFingerprint() uint64 {
+	pcs := make([]uintptr, 32)
+	pcs = pcs[:runtime.Callers(2, pcs)]
+
+	h := fnv.New64a()
+	for _, pc := range pcs {
+		h.Write(strconv.AppendUint(nil, uint64(pc), 16))
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,50 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGroup_HandlerSampling(t *testing.T) {
+	calls := int32(0)
+	handler := func(r interface{}, stack []byte) {
+		atomic.AddInt32(&calls, 1)
+	}
+
+	group := NewGoroutineGroup(context.Background(), handler, WithHandlerSampling(0))
+	for i := 0; i < 10; i++ {
+		group.Go(func(ctx context.Context) {
+			panic("storm")
+		})
+	}
+	group.Go(func(ctx context.Context) {
+		panic("elsewhere")
+	})
+
+	if err := group.Wait(); err == nil {
+		t.Error("Expected an error from Wait(), got nil")
+	}
+	if count := atomic.LoadInt32(&calls); count != 2 {
+		t.Errorf("Expected handler to see first panic of each call path only, got %d calls", count)
+	}
+}
+
+func TestGroup_HandlerSampling_Full(t *testing.T) {
+	calls := int32(0)
+	handler := func(r interface{}, stack []byte) {
+		atomic.AddInt32(&calls, 1)
+	}
+
+	group := NewGoroutineGroup(context.Background(), handler, WithHandlerSampling(1))
+	for i := 0; i < 5; i++ {
+		group.Go(func(ctx context.Context) {
+			panic("storm")
+		})
+	}
+	group.Wait()
+
+	if count := atomic.LoadInt32(&calls); count != 5 {
+		t.Errorf("Expected 5 handler calls, got %d", count)
+	}
+}
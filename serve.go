@@ -0,0 +1,48 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"io"
+)
+
+// Acceptor is a listener accepting connections of type C. net.Listener is an
+// Acceptor[net.Conn].
+type Acceptor[C io.Closer] interface {
+	Accept() (C, error)
+	Close() error
+}
+
+// ServeLoop accepts connections from l in a loop and handles each one in its own
+// panic-protected goroutine, for servers with custom TCP/UDP protocols outside of
+// net/http. Every connection is closed once handle returns or panics.
+//
+// When ctx is done ServeLoop closes l, stops accepting, and drains: it returns
+// once all in-flight handlers have finished, which see ctx cancelled. It also
+// returns when accept fails for another reason, with that error. Handler panics
+// are passed to the panic handler of a group configured by opts but not retained,
+// since a server runs for as long as the process.
+func ServeLoop[C io.Closer](ctx context.Context, l Acceptor[C], handle func(context.Context, C), opts ...Option) error {
+	stop := context.AfterFunc(ctx, func() {
+		l.Close()
+	})
+	defer stop()
+
+	group := NewGoroutineGroup(ctx, nil, opts...)
+	var acceptErr error
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
+				acceptErr = err
+			}
+			break
+		}
+		group.submit(task{transient: true, fn: func(ctx context.Context) error {
+			defer conn.Close()
+			handle(ctx, conn)
+			return nil
+		}})
+	}
+	group.Wait()
+	return acceptErr
+}
@@ -0,0 +1,101 @@
+package goroutine_panic_helper
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestServeLoop(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Cannot listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	handled := int32(0)
+	var panics atomic.Int32
+	served := make(chan error, 1)
+	go func() {
+		served <- ServeLoop(ctx, ln, func(ctx context.Context, conn net.Conn) {
+			atomic.AddInt32(&handled, 1)
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			if strings.TrimSpace(line) == "panic" {
+				panic("handler panic")
+			}
+			conn.Write([]byte("echo " + line))
+		}, WithTaskPanicHandler(func(task string, r interface{}, stack []byte) {
+			panics.Add(1)
+		}))
+	}()
+
+	for _, msg := range []string{"hello", "panic"} {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Write([]byte(msg + "\n"))
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		reply, _ := bufio.NewReader(conn).ReadString('\n')
+		if msg == "hello" && reply != "echo hello\n" {
+			t.Errorf("Unexpected reply: %q", reply)
+		}
+		if msg == "panic" && reply != "" {
+			t.Errorf("Expected connection to be closed after panic, got %q", reply)
+		}
+		conn.Close()
+	}
+
+	cancel()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("Expected the shutdown accept error to be dropped, got: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected ServeLoop to close the listener when ctx is done")
+	}
+	if n := panics.Load(); n != 1 {
+		t.Errorf("Expected the handler panic to be reported, got %d", n)
+	}
+	if n := atomic.LoadInt32(&handled); n != 2 {
+		t.Errorf("Expected 2 handled connections, got %d", n)
+	}
+}
+
+func TestServeLoop_Drain(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Cannot listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	started := make(chan struct{})
+	var drained atomic.Bool
+	served := make(chan error, 1)
+	go func() {
+		served <- ServeLoop(ctx, ln, func(ctx context.Context, conn net.Conn) {
+			close(started)
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond)
+			drained.Store(true)
+		})
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	<-started
+	cancel()
+	if err := <-served; err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !drained.Load() {
+		t.Error("Expected ServeLoop to wait for the in-flight handler")
+	}
+}
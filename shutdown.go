@@ -0,0 +1,66 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrShutdownTimeout is returned by Shutdown when tasks are still running after
+// both phases
+var ErrShutdownTimeout = errors.New("shutdown timed out with tasks still running")
+
+// errHardCancel is the cause of the task context cancellation by Shutdown
+var errHardCancel = errors.New("group shut down")
+
+// Stopping returns a channel that is closed when the group running the task that
+// received ctx begins a soft shutdown. Tasks select on it to finish their current
+// unit of work and return. Outside of a group task the channel is never closed.
+func Stopping(ctx context.Context) <-chan struct{} {
+	if gg := FromContext(ctx); gg != nil {
+		return gg.stopping
+	}
+	return nil
+}
+
+// Shutdown stops the group in two phases. First it closes the channel returned by
+// Stopping, asking tasks to finish their current work, and waits up to grace for
+// them. Then it cancels the context passed to the tasks and waits up to timeout
+// more. It returns the result of Wait once all tasks finished, or
+// ErrShutdownTimeout if some are still running; those are left to finish on their
+// own, and the group's Flushers are flushed regardless. The group should not be
+// used for new tasks afterwards.
+func (gg *GoroutineGroup) Shutdown(grace, timeout time.Duration) error {
+	done := gg.Done()
+	gg.stoppingOnce.Do(func() {
+		close(gg.stopping)
+	})
+	if !waitFor(done, grace) {
+		gg.hardCancel()
+		if !waitFor(done, timeout) {
+			// Flush anyway, reports of the stuck tasks are the ones most likely lost
+			return errors.Join(ErrShutdownTimeout, gg.flush())
+		}
+	}
+	return gg.Wait()
+}
+
+// hardCancel cancels the context of the running tasks
+func (gg *GoroutineGroup) hardCancel() {
+	gg.idleMu.Lock()
+	defer gg.idleMu.Unlock()
+	if gg.stop != nil {
+		gg.stop(errHardCancel)
+	}
+}
+
+func waitFor(done <-chan struct{}, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
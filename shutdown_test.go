@@ -0,0 +1,112 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroup_Shutdown_Soft(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {
+		select {
+		case <-Stopping(ctx):
+			if ctx.Err() != nil {
+				t.Error("Context was cancelled during the soft phase")
+			}
+		case <-time.After(time.Second):
+			t.Error("Soft shutdown was not signalled")
+		}
+	})
+
+	if err := group.Shutdown(time.Second, time.Second); err != nil {
+		t.Errorf("Expected clean shutdown, got: %v", err)
+	}
+}
+
+func TestGroup_Shutdown_Hard(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		if !errors.Is(context.Cause(ctx), errHardCancel) {
+			t.Errorf("Unexpected cancellation cause: %v", context.Cause(ctx))
+		}
+	})
+
+	start := time.Now()
+	if err := group.Shutdown(20*time.Millisecond, time.Second); err != nil {
+		t.Errorf("Expected shutdown after hard cancel, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Hard cancel happened before the grace period, after %v", elapsed)
+	}
+}
+
+func TestGroup_Shutdown_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+
+	if err := group.Shutdown(10*time.Millisecond, 10*time.Millisecond); !errors.Is(err, ErrShutdownTimeout) {
+		t.Errorf("Expected ErrShutdownTimeout, got: %v", err)
+	}
+}
+
+func TestStopping_Outside(t *testing.T) {
+	if Stopping(context.Background()) != nil {
+		t.Error("Expected nil channel outside of a group task")
+	}
+}
+
+func TestGroup_Shutdown_TimeoutFlushes(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	reporter := &queueReporter{}
+	group := NewGoroutineGroup(context.Background(), reporter.handle, WithFlusher(reporter, time.Second))
+	group.Go(func(ctx context.Context) {
+		panic("reported")
+	})
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+
+	if err := group.Shutdown(10*time.Millisecond, 10*time.Millisecond); !errors.Is(err, ErrShutdownTimeout) {
+		t.Errorf("Expected ErrShutdownTimeout, got: %v", err)
+	}
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+	if len(reporter.sent) != 1 {
+		t.Errorf("Expected the panic to be flushed on timeout, sent %v", reporter.sent)
+	}
+}
+
+func TestGroup_TaskContextReleased(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	group := NewGoroutineGroup(parent, nil)
+	var first context.Context
+	group.Go(func(ctx context.Context) {
+		first = ctx
+	})
+	group.Wait()
+	if first.Err() == nil {
+		t.Error("Expected the task context to be released once the group drained")
+	}
+
+	group.Go(func(ctx context.Context) {
+		if ctx.Err() != nil {
+			t.Error("Expected a live context for the next batch")
+		}
+		if FromContext(ctx) != group {
+			t.Error("Expected the group in the new context")
+		}
+	})
+	group.Wait()
+}
@@ -0,0 +1,202 @@
+package goroutine_panic_helper
+
+import (
+	"cmp"
+	"expvar"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const recentPanicsSize = 10
+
+// Snapshot is a serializable view of a group's current state, for support bundles
+// and admin APIs
+type Snapshot struct {
+	Started      int64            `json:"started"`
+	Running      int64            `json:"running"`
+	Completed    int64            `json:"completed"`
+	Panicked     int64            `json:"panicked"`
+	RunningTasks []RunningTask    `json:"running_tasks,omitempty"`
+	RecentPanics []PanicRecord    `json:"recent_panics,omitempty"`
+	RecentEvents []TaskEvent      `json:"recent_events,omitempty"`
+	Overhead     RecoveryOverhead `json:"overhead"`
+	Durations    TaskDurations    `json:"durations"`
+	// Cancelled are the most recent tasks aborted by cancellation, see
+	// RecordCancellations
+	Cancelled []*CancelledError `json:"cancelled,omitempty"`
+	// Submissions are the sampled submission call sites, see WithSubmissionProfile
+	Submissions []SubmissionSite `json:"submissions,omitempty"`
+	// SuccessRates are the outcomes per task name, see WithSuccessRate
+	SuccessRates []SuccessRate `json:"success_rates,omitempty"`
+}
+
+// RecoveryOverhead is the total time the group spent handling panics, so the cost
+// of the recovery machinery can be measured in production
+type RecoveryOverhead struct {
+	StackCapture    time.Duration `json:"stack_capture"`
+	Handler         time.Duration `json:"handler"`
+	ErrorConversion time.Duration `json:"error_conversion"`
+}
+
+// RunningTask describes one task that is running
+type RunningTask struct {
+	ID      int64     `json:"id"`
+	Name    string    `json:"name,omitempty"`
+	Started time.Time `json:"started"`
+}
+
+// TaskDurations summarizes how long finished tasks ran, panicked ones included.
+// Durations of individual tasks are not kept; Total divided by Completed is the
+// mean.
+type TaskDurations struct {
+	Total time.Duration `json:"total"`
+	Max   time.Duration `json:"max"`
+}
+
+// PanicRecord describes one recovered panic
+type PanicRecord struct {
+	Time time.Time `json:"time"`
+	// Submitted and Started are when the panicking task was submitted and began
+	// running, zero for panics of the ForEachSeq2 iterator
+	Submitted time.Time `json:"submitted"`
+	Started   time.Time `json:"started"`
+	Task      string    `json:"task,omitempty"`
+	Value     string    `json:"value"`
+	Location  string    `json:"location,omitempty"`
+	Origin    Origin    `json:"origin"`
+	// Code and Fields are set for PanicValue panics
+	Code   string         `json:"code,omitempty"`
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// Snapshot returns the group's current task counts and durations, the tasks that
+// are running with their start times, its most recent panics, and recent task
+// events when WithEventLog is enabled, newest last. Submission call sites are
+// included when WithSubmissionProfile is enabled.
+func (gg *GoroutineGroup) Snapshot() Snapshot {
+	completed := gg.stats.completed.Load()
+	started := gg.stats.started.Load()
+	return Snapshot{
+		Started:      started,
+		Running:      started - completed,
+		Completed:    completed,
+		Panicked:     gg.stats.panicked.Load(),
+		RunningTasks: gg.stats.runningTasks(),
+		RecentPanics: gg.stats.recentPanics(),
+		Overhead: RecoveryOverhead{
+			StackCapture:    time.Duration(gg.stats.stackCapture.Load()),
+			Handler:         time.Duration(gg.stats.handlerTime.Load()),
+			ErrorConversion: time.Duration(gg.stats.errorConversion.Load()),
+		},
+		Durations: TaskDurations{
+			Total: time.Duration(gg.stats.durationTotal.Load()),
+			Max:   time.Duration(gg.stats.durationMax.Load()),
+		},
+		RecentEvents: gg.events.snapshot(),
+		Cancelled:    gg.stats.recentCancelled(),
+		Submissions:  gg.submissions.snapshot(),
+		SuccessRates: gg.SuccessRates(),
+	}
+}
+
+// PublishExpvar publishes the group's Snapshot as the expvar variable name. Like
+// expvar.Publish it panics if the name is already in use.
+func (gg *GoroutineGroup) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return gg.Snapshot()
+	}))
+}
+
+type groupStats struct {
+	started   atomic.Int64
+	completed atomic.Int64
+	panicked  atomic.Int64
+
+	// recovery overhead in nanoseconds
+	stackCapture    atomic.Int64
+	handlerTime     atomic.Int64
+	errorConversion atomic.Int64
+
+	// task durations in nanoseconds
+	durationTotal atomic.Int64
+	durationMax   atomic.Int64
+
+	mu        sync.Mutex
+	panics    []PanicRecord
+	cancelled []*CancelledError
+	running   map[int64]RunningTask
+}
+
+func (s *groupStats) addRunning(t RunningTask) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running == nil {
+		s.running = make(map[int64]RunningTask)
+	}
+	s.running[t.ID] = t
+}
+
+func (s *groupStats) removeRunning(id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, id)
+}
+
+// runningTasks returns the running tasks ordered by ID, that is by start
+func (s *groupStats) runningTasks() []RunningTask {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.running) == 0 {
+		return nil
+	}
+	return slices.SortedFunc(maps.Values(s.running), func(a, b RunningTask) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+func (s *groupStats) recordDuration(d time.Duration) {
+	s.durationTotal.Add(int64(d))
+	for {
+		longest := s.durationMax.Load()
+		if int64(d) <= longest || s.durationMax.CompareAndSwap(longest, int64(d)) {
+			return
+		}
+	}
+}
+
+func (s *groupStats) recordPanic(t task, r any, stack []byte) {
+	s.panicked.Add(1)
+	record := PanicRecord{
+		Time:      time.Now(),
+		Submitted: t.submitted,
+		Started:   t.started,
+		Task:      t.name,
+		Value:     fmt.Sprint(r),
+		Location:  stackHead(stack, 1),
+		Origin:    ClassifyOrigin(stack),
+	}
+	if v, ok := r.(*PanicValue); ok {
+		record.Code, record.Fields = v.Code, v.Fields
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.panics) == recentPanicsSize {
+		copy(s.panics, s.panics[1:])
+		s.panics = s.panics[:recentPanicsSize-1]
+	}
+	s.panics = append(s.panics, record)
+}
+
+func (s *groupStats) recentPanics() []PanicRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.panics) == 0 {
+		return nil
+	}
+	return append([]PanicRecord(nil), s.panics...)
+}
@@ -0,0 +1,125 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroup_Snapshot(t *testing.T) {
+	release := make(chan struct{})
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	for i := 0; i < 12; i++ {
+		group.Go(func(ctx context.Context) {
+			panic(fmt.Sprintf("panic %d", i))
+		})
+	}
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+	for group.Snapshot().Running > 1 {
+		runtime.Gosched()
+	}
+
+	snap := group.Snapshot()
+	if snap.Started != 13 || snap.Running != 1 || snap.Completed != 12 {
+		t.Errorf("Unexpected counts: %+v", snap)
+	}
+	if len(snap.RecentPanics) != recentPanicsSize {
+		t.Errorf("Expected %d recent panics, got %d", recentPanicsSize, len(snap.RecentPanics))
+	}
+	if !strings.Contains(snap.RecentPanics[0].Location, "snapshot_test.go") {
+		t.Errorf("Expected panic location in snapshot_test.go, got %q", snap.RecentPanics[0].Location)
+	}
+
+	close(release)
+	group.Wait()
+	if snap := group.Snapshot(); snap.Running != 0 || snap.Completed != 13 {
+		t.Errorf("Unexpected counts after Wait: %+v", snap)
+	}
+	if _, err := json.Marshal(group.Snapshot()); err != nil {
+		t.Errorf("Snapshot is not serializable: %v", err)
+	}
+}
+
+func TestGroup_Snapshot_Overhead(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {
+		time.Sleep(5 * time.Millisecond)
+	})
+	group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+	group.Wait()
+
+	overhead := group.Snapshot().Overhead
+	if overhead.StackCapture <= 0 || overhead.ErrorConversion <= 0 {
+		t.Errorf("Expected recovery overhead to be measured, got %+v", overhead)
+	}
+	if overhead.Handler < 5*time.Millisecond {
+		t.Errorf("Expected handler time of at least 5ms, got %v", overhead.Handler)
+	}
+}
+
+func TestGroup_Snapshot_Durations(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {
+		time.Sleep(20 * time.Millisecond)
+	})
+	group.Go(func(ctx context.Context) {
+		time.Sleep(5 * time.Millisecond)
+		panic("boom")
+	})
+	group.Wait()
+
+	durations := group.Snapshot().Durations
+	if durations.Max < 20*time.Millisecond {
+		t.Errorf("Expected a max duration of at least 20ms, got %v", durations.Max)
+	}
+	if durations.Total < 25*time.Millisecond {
+		t.Errorf("Expected the panicked task to count towards the total, got %v", durations.Total)
+	}
+}
+
+func TestGroup_Snapshot_RunningTasks(t *testing.T) {
+	release := make(chan struct{})
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.GoNamed("migrate", func(ctx context.Context) {
+		<-release
+	})
+	group.Go(func(ctx context.Context) {})
+	for group.Snapshot().Running > 1 {
+		runtime.Gosched()
+	}
+
+	running := group.Snapshot().RunningTasks
+	if len(running) != 1 || running[0].Name != "migrate" || running[0].ID != 1 || running[0].Started.IsZero() {
+		t.Errorf("Expected the running task with its start time, got %+v", running)
+	}
+	close(release)
+	group.Wait()
+	if running := group.Snapshot().RunningTasks; running != nil {
+		t.Errorf("Expected no running tasks after Wait, got %+v", running)
+	}
+}
+
+func TestGroup_PublishExpvar(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {})
+	group.Wait()
+	// expvar names are global, so make the name unique for repeated runs
+	name := fmt.Sprintf("test_group_%p", group)
+	group.PublishExpvar(name)
+
+	var snapshot Snapshot
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &snapshot); err != nil {
+		t.Fatalf("Invalid expvar JSON: %v", err)
+	}
+	if snapshot.Completed != 1 {
+		t.Errorf("Expected 1 completed task, got %+v", snapshot)
+	}
+}
@@ -0,0 +1,174 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+)
+
+// Produce runs fn in a panic-protected goroutine, feeding out through emit. emit
+// blocks until the consumer receives the value and returns ctx.Err() once ctx is
+// cancelled, so a producer whose consumer went away stops instead of leaking. out
+// is closed exactly once when fn returns or panics. The returned channel delivers
+// fn's error, or the panic-derived error, and is then closed. Panics are passed to
+// handler; a nil handler uses DefaultPanicHandler.
+func Produce[T any](ctx context.Context, out chan<- T, fn func(ctx context.Context, emit func(T) error) error, handler PanicHandler) <-chan error {
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+
+		var err error
+		group := NewGoroutineGroup(ctx, handler)
+		group.Go(func(ctx context.Context) {
+			defer close(out)
+			err = fn(ctx, func(v T) error {
+				select {
+				case out <- v:
+					return nil
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			})
+		})
+		panicErr := group.Wait()
+		errc <- errors.Join(err, panicErr)
+	}()
+	return errc
+}
+
+// Merge forwards values from all chans to the returned channel, which is closed
+// once every input is closed or ctx is cancelled. On cancellation the forwarding
+// goroutines exit without draining their inputs.
+func Merge[T any](ctx context.Context, chans ...<-chan T) <-chan T {
+	out := make(chan T)
+	group := NewGoroutineGroup(ctx, nil)
+	for _, in := range chans {
+		group.Go(func(ctx context.Context) {
+			for {
+				select {
+				case v, ok := <-in:
+					if !ok {
+						return
+					}
+					select {
+					case out <- v:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+	go func() {
+		group.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Result is the outcome of processing one item: its value, or the error returned
+// or converted from a panic while producing it
+type Result[R any] struct {
+	Value R
+	Err   error
+}
+
+// Sequence applies fn to the values from in with up to workers concurrent calls
+// and delivers the results in input order. A panic in fn becomes that item's
+// error and is passed to handler, or DefaultPanicHandler if nil. At most workers
+// items are in flight or buffered for reordering at any time. The returned channel
+// is closed when in is closed and drained, or when ctx is cancelled, once all
+// calls to fn returned.
+func Sequence[T, R any](ctx context.Context, in <-chan T, workers int, fn func(context.Context, T) (R, error), handler PanicHandler) <-chan Result[R] {
+	if workers < 1 {
+		workers = 1
+	}
+	if handler == nil {
+		handler = DefaultPanicHandler
+	}
+	out := make(chan Result[R])
+	pending := make(chan chan Result[R], workers-1)
+
+	group := NewGoroutineGroup(ctx, handler)
+	group.Go(func(ctx context.Context) {
+		defer close(pending)
+		for {
+			var v T
+			select {
+			case item, ok := <-in:
+				if !ok {
+					return
+				}
+				v = item
+			case <-ctx.Done():
+				return
+			}
+
+			done := make(chan Result[R], 1)
+			select {
+			case pending <- done:
+			case <-ctx.Done():
+				return
+			}
+			group.Go(func(ctx context.Context) {
+				var r Result[R]
+				if err := runProtected(handler, func() {
+					r.Value, r.Err = fn(ctx, v)
+				}); err != nil {
+					r.Err = err
+				}
+				done <- r
+			})
+		}
+	})
+
+	go func() {
+		defer close(out)
+		if !forwardResults(ctx, pending, out) {
+			group.Wait()
+			return
+		}
+		// Item panics are converted by runProtected, so an error here comes from
+		// the dispatcher itself
+		if err := group.Wait(); err != nil {
+			select {
+			case out <- Result[R]{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return out
+}
+
+// forwardResults delivers the results of pending items to out in order and reports
+// whether all of them were delivered before ctx was done
+func forwardResults[R any](ctx context.Context, pending <-chan chan Result[R], out chan<- Result[R]) bool {
+	for done := range pending {
+		select {
+		case r := <-done:
+			select {
+			case out <- r:
+			case <-ctx.Done():
+				return false
+			}
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// runProtected calls fn and converts a panic into an error after passing it to handler
+func runProtected(handler PanicHandler, fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			handler(r, stack)
+			err = ErrorFormat{}.toError("", r, stack)
+		}
+	}()
+	fn()
+	return nil
+}
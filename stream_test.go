@@ -0,0 +1,170 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProduce(t *testing.T) {
+	out := make(chan int)
+	errc := Produce(context.Background(), out, func(ctx context.Context, emit func(int) error) error {
+		for i := 0; i < 3; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil)
+
+	var got []int
+	for v := range out {
+		got = append(got, v)
+	}
+	if len(got) != 3 {
+		t.Errorf("Expected 3 values, got %v", got)
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestProduce_Panic(t *testing.T) {
+	out := make(chan int)
+	errc := Produce(context.Background(), out, func(ctx context.Context, emit func(int) error) error {
+		emit(1)
+		panic("producer panic")
+	}, noopHandler)
+
+	for range out {
+	}
+	if err := <-errc; err == nil || !strings.Contains(err.Error(), "producer panic") {
+		t.Errorf("Expected producer panic error, got: %v", err)
+	}
+}
+
+func TestProduce_ConsumerGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan int)
+	errc := Produce(ctx, out, func(ctx context.Context, emit func(int) error) error {
+		for i := 0; ; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+	}, nil)
+
+	<-out
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+	if _, ok := <-out; ok {
+		t.Error("Expected out to be closed")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a, b := make(chan int), make(chan int)
+	go func() {
+		defer close(a)
+		for i := 0; i < 3; i++ {
+			a <- i
+		}
+	}()
+	go func() {
+		defer close(b)
+		for i := 10; i < 12; i++ {
+			b <- i
+		}
+	}()
+
+	sum, count := 0, 0
+	for v := range Merge(context.Background(), a, b) {
+		sum += v
+		count++
+	}
+	if count != 5 || sum != 24 {
+		t.Errorf("Expected 5 values summing to 24, got %d values summing to %d", count, sum)
+	}
+}
+
+func TestMerge_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	never := make(chan int)
+	out := Merge(ctx, never, never)
+	cancel()
+
+	if _, ok := <-out; ok {
+		t.Error("Expected merged channel to be closed after cancellation")
+	}
+}
+
+func TestSequence(t *testing.T) {
+	in := make(chan int)
+	go func() {
+		defer close(in)
+		for i := 0; i < 20; i++ {
+			in <- i
+		}
+	}()
+
+	results := Sequence(context.Background(), in, 4, func(ctx context.Context, v int) (int, error) {
+		time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+		if v == 7 {
+			panic("item panic")
+		}
+		return v * v, nil
+	}, noopHandler)
+
+	i := 0
+	for r := range results {
+		if i == 7 {
+			if r.Err == nil || !strings.Contains(r.Err.Error(), "item panic") {
+				t.Errorf("Expected panic error for item 7, got: %v", r.Err)
+			}
+		} else if r.Err != nil || r.Value != i*i {
+			t.Errorf("Item %d: expected %d, got %d (err %v)", i, i*i, r.Value, r.Err)
+		}
+		i++
+	}
+	if i != 20 {
+		t.Errorf("Expected 20 results, got %d", i)
+	}
+}
+
+func TestSequence_StrictWait(t *testing.T) {
+	reported := make(chan LeakReport, 1)
+	SetStrictWait(func(r LeakReport) {
+		select {
+		case reported <- r:
+		default:
+		}
+	})
+	defer SetStrictWait(nil)
+
+	func() {
+		in := make(chan int, 3)
+		for i := range 3 {
+			in <- i
+		}
+		close(in)
+		for range Sequence(context.Background(), in, 2, func(ctx context.Context, v int) (int, error) {
+			return v, nil
+		}, nil) {
+		}
+	}()
+
+	for range 3 {
+		runtime.GC()
+	}
+	select {
+	case r := <-reported:
+		t.Errorf("Unexpected leak report for Sequence:\n%s", r.Stack)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,106 @@
+package goroutine_panic_helper
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const packagePath = "github.com/onurburak9/goroutine-panic-helper"
+
+// SubmissionSite is a code location that submitted tasks to a group
+type SubmissionSite struct {
+	Function string `json:"function"`
+	Location string `json:"location"`
+	// Samples is the number of sampled submissions from the site
+	Samples int64 `json:"samples"`
+}
+
+// WithSubmissionProfile records the call site of a fraction rate (0 to 1) of task
+// submissions, so when a group is flooded the responsible code path can be found
+// without adding logging. The sites are available from SubmissionSites and are
+// included in Snapshot.
+func WithSubmissionProfile(rate float64) Option {
+	return func(gg *GoroutineGroup) {
+		if rate <= 0 {
+			gg.submissions = nil
+			return
+		}
+		gg.submissions = &submissionProfile{rate: rate, sites: make(map[uintptr]*SubmissionSite)}
+	}
+}
+
+// SubmissionSites returns the sampled submission call sites, most frequent first.
+// It returns nil unless the group was created with WithSubmissionProfile.
+func (gg *GoroutineGroup) SubmissionSites() []SubmissionSite {
+	return gg.submissions.snapshot()
+}
+
+type submissionProfile struct {
+	rate  float64
+	mu    sync.Mutex
+	sites map[uintptr]*SubmissionSite
+}
+
+// record samples the calling submission, attributing it to the first frame
+// outside of this package
+func (p *submissionProfile) record() {
+	if p == nil || rand.Float64() >= p.rate {
+		return
+	}
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !isPackageFrame(frame) {
+			p.add(frame)
+			return
+		}
+		if !more {
+			return
+		}
+	}
+}
+
+func (p *submissionProfile) add(frame runtime.Frame) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	site, ok := p.sites[frame.PC]
+	if !ok {
+		site = &SubmissionSite{
+			Function: frame.Function,
+			Location: frame.File + ":" + strconv.Itoa(frame.Line),
+		}
+		p.sites[frame.PC] = site
+	}
+	site.Samples++
+}
+
+func (p *submissionProfile) snapshot() []SubmissionSite {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	sites := make([]SubmissionSite, 0, len(p.sites))
+	for _, site := range p.sites {
+		sites = append(sites, *site)
+	}
+	p.mu.Unlock()
+
+	slices.SortFunc(sites, func(a, b SubmissionSite) int {
+		return cmp.Or(cmp.Compare(b.Samples, a.Samples), strings.Compare(a.Location, b.Location))
+	})
+	return sites
+}
+
+// isPackageFrame reports whether frame belongs to this package's non-test code
+func isPackageFrame(frame runtime.Frame) bool {
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+	return strings.HasPrefix(frame.Function, packagePath+".")
+}
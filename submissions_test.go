@@ -0,0 +1,45 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGroup_SubmissionProfile(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil, WithSubmissionProfile(1))
+	for range 3 {
+		group.Go(func(ctx context.Context) {})
+	}
+	group.GoNamed("named", func(ctx context.Context) {})
+	group.Wait()
+
+	sites := group.SubmissionSites()
+	if len(sites) != 2 {
+		t.Fatalf("Expected 2 submission sites, got %+v", sites)
+	}
+	if sites[0].Samples != 3 || sites[1].Samples != 1 {
+		t.Errorf("Unexpected sample counts: %+v", sites)
+	}
+	for _, site := range sites {
+		if !strings.HasSuffix(site.Function, "TestGroup_SubmissionProfile") {
+			t.Errorf("Expected the test as submitter, got %q", site.Function)
+		}
+		if !strings.Contains(site.Location, "submissions_test.go:") {
+			t.Errorf("Unexpected location %q", site.Location)
+		}
+	}
+	if len(group.Snapshot().Submissions) != 2 {
+		t.Error("Expected submission sites in snapshot")
+	}
+}
+
+func TestGroup_SubmissionProfile_Disabled(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {})
+	group.Wait()
+
+	if sites := group.SubmissionSites(); sites != nil {
+		t.Errorf("Expected no sites without WithSubmissionProfile, got %+v", sites)
+	}
+}
@@ -0,0 +1,112 @@
+package goroutine_panic_helper
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithSuccessRate tracks how many tasks of each name succeeded and failed over the
+// last minute, turning the group into a lightweight SLO monitor for background
+// work. A task fails if it returns an error or panics; unnamed tasks are not
+// tracked. alert, if not nil, is called each time the success rate of a name drops
+// below target (0 to 1), and again after it has recovered and drops anew.
+func WithSuccessRate(target float64, alert func(name string, rate SuccessRate)) Option {
+	return func(gg *GoroutineGroup) {
+		gg.successRate = &successRates{target: target, alert: alert, names: make(map[string]*nameRate)}
+	}
+}
+
+// SuccessRate is the outcome of the tasks of one name during the last minute
+type SuccessRate struct {
+	Name      string  `json:"name"`
+	Succeeded int     `json:"succeeded"`
+	Failed    int     `json:"failed"`
+	Rate      float64 `json:"rate"`
+}
+
+// SuccessRates returns the success rate of every task name seen during the last
+// minute, sorted by name. It is only tracked when the group was created with
+// WithSuccessRate, and included in Snapshot.
+func (gg *GoroutineGroup) SuccessRates() []SuccessRate {
+	if gg.successRate == nil {
+		return nil
+	}
+	return gg.successRate.rates(time.Now())
+}
+
+// successRates counts task outcomes per name in one-second buckets over a sliding
+// minute, like panicRate
+type successRates struct {
+	target float64
+	alert  func(string, SuccessRate)
+
+	mu    sync.Mutex
+	names map[string]*nameRate
+}
+
+type nameRate struct {
+	succeeded [60]int
+	failed    [60]int
+	seconds   [60]int64
+	below     bool
+}
+
+func (s *successRates) record(name string, ok bool, now time.Time) {
+	s.mu.Lock()
+	r := s.names[name]
+	if r == nil {
+		r = &nameRate{}
+		s.names[name] = r
+	}
+	sec := now.Unix()
+	i := sec % 60
+	if r.seconds[i] != sec {
+		r.seconds[i] = sec
+		r.succeeded[i], r.failed[i] = 0, 0
+	}
+	if ok {
+		r.succeeded[i]++
+	} else {
+		r.failed[i]++
+	}
+	rate := r.sumLocked(name, sec)
+	below := rate.Rate < s.target
+	raised := below && !r.below
+	r.below = below
+	s.mu.Unlock()
+
+	if raised && s.alert != nil {
+		s.alert(name, rate)
+	}
+}
+
+func (s *successRates) rates(now time.Time) []SuccessRate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rates []SuccessRate
+	for name, r := range s.names {
+		if rate := r.sumLocked(name, now.Unix()); rate.Succeeded+rate.Failed > 0 {
+			rates = append(rates, rate)
+		}
+	}
+	slices.SortFunc(rates, func(a, b SuccessRate) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return rates
+}
+
+func (r *nameRate) sumLocked(name string, sec int64) SuccessRate {
+	rate := SuccessRate{Name: name}
+	for i := range r.seconds {
+		if sec-r.seconds[i] < 60 {
+			rate.Succeeded += r.succeeded[i]
+			rate.Failed += r.failed[i]
+		}
+	}
+	if total := rate.Succeeded + rate.Failed; total > 0 {
+		rate.Rate = float64(rate.Succeeded) / float64(total)
+	}
+	return rate
+}
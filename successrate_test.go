@@ -0,0 +1,37 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestWithSuccessRate(t *testing.T) {
+	var alerts []SuccessRate
+	group := NewGoroutineGroup(context.Background(), noopHandler, WithSuccessRate(0.75, func(name string, rate SuccessRate) {
+		alerts = append(alerts, rate)
+	}))
+	group.SetLimit(1)
+
+	for i := 0; i < 3; i++ {
+		group.GoNamed("sync", func(ctx context.Context) {})
+	}
+	group.GoNamed("sync", func(ctx context.Context) {
+		panic("sync failed")
+	})
+	group.GoNamed("sync", func(ctx context.Context) {
+		panic("sync failed again")
+	})
+	group.GoErr(func(ctx context.Context) error {
+		return errors.New("unnamed")
+	})
+	group.Wait()
+
+	rates := group.Snapshot().SuccessRates
+	if len(rates) != 1 || rates[0] != (SuccessRate{Name: "sync", Succeeded: 3, Failed: 2, Rate: 0.6}) {
+		t.Errorf("Expected only the named tasks to be tracked, got %+v", rates)
+	}
+	if len(alerts) != 1 || alerts[0].Failed != 2 {
+		t.Errorf("Expected one alert when the rate dropped below 75%%, got %+v", alerts)
+	}
+}
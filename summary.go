@@ -0,0 +1,83 @@
+package goroutine_panic_helper
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SummaryError is a compact summary of many task errors, grouped by message. It
+// unwraps to the individual errors, so errors.Is and errors.As still see them.
+type SummaryError struct {
+	errs   []error
+	counts map[string]int
+	labels map[string]string
+	order  []string
+}
+
+// Summarize flattens joined errors (such as those returned by WaitAllGroups) and
+// groups them by message, producing e.g. "37 tasks failed: 30× nil deref, 7×
+// timeout". Panic errors are grouped by panic value and location instead, so the
+// same panic in differently named tasks, such as the items of Map, counts once. It
+// returns nil for a nil error and single errors unchanged.
+func Summarize(err error) error {
+	errs := flattenErrors(err, nil)
+	if len(errs) <= 1 {
+		return err
+	}
+
+	summary := &SummaryError{errs: errs, counts: make(map[string]int), labels: make(map[string]string)}
+	for _, e := range errs {
+		key, label := summaryKey(e)
+		if summary.counts[key] == 0 {
+			summary.order = append(summary.order, key)
+			summary.labels[key] = label
+		}
+		summary.counts[key]++
+	}
+	sort.SliceStable(summary.order, func(i, j int) bool {
+		return summary.counts[summary.order[i]] > summary.counts[summary.order[j]]
+	})
+	return summary
+}
+
+func (e *SummaryError) Error() string {
+	parts := make([]string, len(e.order))
+	for i, key := range e.order {
+		parts[i] = fmt.Sprintf("%d× %s", e.counts[key], e.labels[key])
+	}
+	return fmt.Sprintf("%d tasks failed: %s", len(e.errs), strings.Join(parts, ", "))
+}
+
+func (e *SummaryError) Unwrap() []error {
+	return e.errs
+}
+
+// summaryKey returns the key err is grouped by and the label shown for its group.
+// Panic errors are keyed by a fingerprint of value and panic location, without the
+// task name, and other errors by their message.
+func summaryKey(err error) (key, label string) {
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		return "error\x00" + err.Error(), err.Error()
+	}
+	label = fmt.Sprintf("panic: %v", panicErr.value)
+	if head := stackHead(panicErr.stack, 1); head != "" {
+		label += " (at " + head + ")"
+	}
+	return "panic\x00" + label, label
+}
+
+func flattenErrors(err error, into []error) []error {
+	if err == nil {
+		return into
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			into = flattenErrors(e, into)
+		}
+		return into
+	}
+	return append(into, err)
+}
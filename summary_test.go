@@ -0,0 +1,70 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	timeout := errors.New("timeout")
+	errs := []error{timeout, errors.New("nil deref"), timeout}
+	for i := 0; i < 3; i++ {
+		errs = append(errs, errors.Join(errors.New("nil deref")))
+	}
+
+	err := Summarize(errors.Join(errs...))
+	if err == nil {
+		t.Fatal("Expected a summary error, got nil")
+	}
+	if got, want := err.Error(), "6 tasks failed: 4× nil deref, 2× timeout"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if !errors.Is(err, timeout) {
+		t.Error("Expected summary to unwrap to the individual errors")
+	}
+}
+
+func TestSummarize_SingleAndNil(t *testing.T) {
+	if Summarize(nil) != nil {
+		t.Error("Expected nil summary for nil error")
+	}
+	single := errors.New("single")
+	if Summarize(single) != single {
+		t.Error("Expected single error to be returned unchanged")
+	}
+}
+
+func TestSummarize_Groups(t *testing.T) {
+	handler := func(r interface{}, stack []byte) {}
+	var groups []*GoroutineGroup
+	for i := 0; i < 3; i++ {
+		group := NewGoroutineGroup(context.Background(), handler)
+		group.Go(func(ctx context.Context) {
+			panic("parse failed")
+		})
+		groups = append(groups, group)
+	}
+
+	err := Summarize(WaitAllGroups(context.Background(), groups...))
+	if got, want := err.Error(), "3 tasks failed: 3× panic: parse failed (at "; !strings.HasPrefix(got, want) {
+		t.Errorf("Expected prefix %q, got %q", want, got)
+	}
+}
+
+func TestSummarize_Map(t *testing.T) {
+	_, err := Map(context.Background(), []int{0, 1, 2, 3}, func(ctx context.Context, i int) (int, error) {
+		var m map[int]int
+		m[i] = i
+		return i, nil
+	}, WithTaskPanicHandler(func(task string, r interface{}, stack []byte) {}))
+
+	msg := Summarize(err).Error()
+	if !strings.HasPrefix(msg, "4 tasks failed: 4× panic: assignment to entry in nil map (at ") {
+		t.Errorf("Expected the item panics to be grouped, got %q", msg)
+	}
+	if !strings.Contains(msg, "summary_test.go") || strings.Contains(msg, "item") {
+		t.Errorf("Expected the panic location without task names, got %q", msg)
+	}
+}
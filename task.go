@@ -0,0 +1,117 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Task is a handle to one task started by Go or one of its variants, for waiting
+// on it alone.
+// The group still tracks the task for Wait.
+type Task struct {
+	done chan struct{}
+	err  error
+
+	mu        sync.Mutex
+	submitted time.Time
+	started   time.Time
+	finished  time.Time
+}
+
+func newTask() *Task {
+	return &Task{done: make(chan struct{})}
+}
+
+// begin records when the task started running
+func (t *Task) begin(started time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.started = started
+}
+
+// finish records the task's error and closes Done
+func (t *Task) finish(err error) {
+	t.mu.Lock()
+	t.err = err
+	t.finished = time.Now()
+	t.mu.Unlock()
+	close(t.done)
+}
+
+// Times returns when the task was submitted, started running, and finished. Times
+// not reached yet are zero; Started minus Submitted is the time spent waiting for
+// a slot under SetLimit.
+func (t *Task) Times() (submitted, started, finished time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.submitted, t.started, t.finished
+}
+
+// Done returns a channel that is closed when the task has finished
+func (t *Task) Done() <-chan struct{} {
+	return t.done
+}
+
+// Err returns the error the task returned or its panic was converted to, nil while
+// it is still running
+func (t *Task) Err() error {
+	select {
+	case <-t.done:
+		return t.err
+	default:
+		return nil
+	}
+}
+
+// Await blocks until the task has finished and returns its error, or returns
+// ctx.Err() if ctx is done first
+func (t *Task) Await(ctx context.Context) error {
+	select {
+	case <-t.done:
+		return t.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ErrNoTasks is returned by AwaitAny when it is given no tasks
+var ErrNoTasks = errors.New("no tasks to await")
+
+// AwaitAny blocks until the first of tasks has finished and returns it along with
+// its error. It returns ctx.Err() if ctx is done first, and ErrNoTasks without
+// tasks.
+func AwaitAny(ctx context.Context, tasks ...*Task) (*Task, error) {
+	if len(tasks) == 0 {
+		return nil, ErrNoTasks
+	}
+	cases := make([]reflect.SelectCase, 0, len(tasks)+1)
+	for _, t := range tasks {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.done)})
+	}
+	cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	chosen, _, _ := reflect.Select(cases)
+	if chosen == len(tasks) {
+		return nil, ctx.Err()
+	}
+	return tasks[chosen], tasks[chosen].err
+}
+
+// AwaitAll blocks until all tasks have finished and returns their errors joined,
+// or returns ctx.Err() if ctx is done first
+func AwaitAll(ctx context.Context, tasks ...*Task) error {
+	var errs []error
+	for _, t := range tasks {
+		select {
+		case <-t.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if t.err != nil {
+			errs = append(errs, t.err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,175 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTask_Await(t *testing.T) {
+	release := make(chan struct{})
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	slow := group.Go(func(ctx context.Context) {
+		<-release
+	})
+	failing := group.GoErr(func(ctx context.Context) error {
+		return errors.New("task failed")
+	})
+	panicking := group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+
+	if err := failing.Await(context.Background()); err == nil || err.Error() != "task failed" {
+		t.Errorf("Expected the returned error, got: %v", err)
+	}
+	if err := panicking.Await(context.Background()); !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected the panic error, got: %v", err)
+	}
+
+	if slow.Err() != nil {
+		t.Error("Expected no error while running")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := slow.Await(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the await to time out, got: %v", err)
+	}
+
+	close(release)
+	<-slow.Done()
+	if slow.Err() != nil {
+		t.Errorf("Expected no error, got: %v", slow.Err())
+	}
+	if err := group.Wait(); err == nil {
+		t.Error("Expected the group to track all tasks")
+	}
+}
+
+func TestTask_Variants(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), noopHandler)
+	named := group.GoNamed("named", func(ctx context.Context) {
+		panic("named panic")
+	})
+	handled := group.GoWithHandler(func(ctx context.Context) {
+		panic("handler panic")
+	}, noopHandler)
+	timed := group.GoWithTimeout(time.Millisecond, func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	with := GoWith(group, 1, func(ctx context.Context, arg int) {})
+	tried, ok := group.TryGoTask(func(ctx context.Context) {})
+	if !ok {
+		t.Fatal("Expected TryGoTask to start a task without a limit")
+	}
+
+	if err := named.Await(context.Background()); !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected the GoNamed panic error, got: %v", err)
+	}
+	if err := handled.Await(context.Background()); !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected the GoWithHandler panic error, got: %v", err)
+	}
+	if err := timed.Await(context.Background()); !errors.Is(err, ErrTaskTimeout) {
+		t.Errorf("Expected the GoWithTimeout error, got: %v", err)
+	}
+	for _, handle := range []*Task{with, tried} {
+		if err := handle.Await(context.Background()); err != nil {
+			t.Errorf("Expected no error, got: %v", err)
+		}
+	}
+	group.Wait()
+}
+
+func TestAwaitAny(t *testing.T) {
+	release := make(chan struct{})
+	group := NewGoroutineGroup(context.Background(), noopHandler)
+	slow := group.Go(func(ctx context.Context) {
+		<-release
+	})
+	fast := group.GoErr(func(ctx context.Context) error {
+		return errors.New("fast failed")
+	})
+
+	first, err := AwaitAny(context.Background(), slow, fast)
+	if first != fast || err == nil || err.Error() != "fast failed" {
+		t.Errorf("Expected the fast task and its error, got %p, %v", first, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if first, err := AwaitAny(ctx, slow); first != nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the await to time out, got %p, %v", first, err)
+	}
+	if _, err := AwaitAny(context.Background()); !errors.Is(err, ErrNoTasks) {
+		t.Errorf("Expected ErrNoTasks, got: %v", err)
+	}
+	close(release)
+	group.Wait()
+}
+
+func TestAwaitAll(t *testing.T) {
+	release := make(chan struct{})
+	group := NewGoroutineGroup(context.Background(), noopHandler)
+	tasks := []*Task{
+		group.Go(func(ctx context.Context) {
+			<-release
+		}),
+		group.GoErr(func(ctx context.Context) error {
+			return errors.New("task failed")
+		}),
+		group.Go(func(ctx context.Context) {
+			panic("boom")
+		}),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := AwaitAll(ctx, tasks...); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the await to time out, got: %v", err)
+	}
+
+	close(release)
+	err := AwaitAll(context.Background(), tasks...)
+	if err == nil || !strings.Contains(err.Error(), "task failed") || !errors.Is(err, ErrPanic) {
+		t.Errorf("Expected both failures joined, got: %v", err)
+	}
+	if err := AwaitAll(context.Background()); err != nil {
+		t.Errorf("Expected no error without tasks, got: %v", err)
+	}
+	group.Wait()
+}
+
+func TestTask_Times(t *testing.T) {
+	group := NewGoroutineGroup(context.Background(), noopHandler)
+	group.SetLimit(1)
+	release := make(chan struct{})
+	first := group.Go(func(ctx context.Context) {
+		<-release
+	})
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	second := group.Go(func(ctx context.Context) {
+		time.Sleep(5 * time.Millisecond)
+		panic("boom")
+	})
+	group.Wait()
+
+	submitted, started, finished := second.Times()
+	if queued := started.Sub(submitted); queued < 5*time.Millisecond {
+		t.Errorf("Expected the second task to wait for a slot, queued %v", queued)
+	}
+	if ran := finished.Sub(started); ran < 5*time.Millisecond {
+		t.Errorf("Expected an execution time of at least 5ms, got %v", ran)
+	}
+	if submitted, _, _ := first.Times(); submitted.IsZero() {
+		t.Error("Expected the submission time of the first task")
+	}
+
+	record := group.Snapshot().RecentPanics[0]
+	if !record.Submitted.Equal(submitted) || !record.Started.Equal(started) {
+		t.Errorf("Expected the task times in the panic record, got %+v", record)
+	}
+}
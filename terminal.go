@@ -0,0 +1,145 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	progressBarWidth = 30
+	// maxSpinnerTasks is the number of running tasks shown with their own spinner
+	maxSpinnerTasks = 8
+)
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// TerminalReporter renders the progress of a group on a terminal: a spinner for
+// every running task, a progress bar, and a final summary of successes, failures,
+// and panics. It is meant for developer tools that use a group to parallelize
+// local work such as builds, migrations, or scans. Pass its Observe method to
+// WithTaskObserver to get the per-task spinners.
+type TerminalReporter struct {
+	w  io.Writer
+	mu sync.Mutex
+	// running maps the ids of running tasks to their names
+	running     map[int64]string
+	done, total int
+	frame       int
+	// drawn is the number of lines of the last frame
+	drawn int
+}
+
+// NewTerminalReporter creates a TerminalReporter writing to w, usually os.Stderr
+func NewTerminalReporter(w io.Writer) *TerminalReporter {
+	return &TerminalReporter{w: w, running: make(map[int64]string)}
+}
+
+// Wait waits for gg with WaitWithProgress, rendering its progress, writes the
+// summary, and returns the error from Wait
+func (r *TerminalReporter) Wait(ctx context.Context, gg *GoroutineGroup) error {
+	err := gg.WaitWithProgress(ctx, r.Progress)
+	r.Summary(gg, err)
+	return err
+}
+
+// Observe tracks running tasks for their spinners. It is meant to be passed to
+// WithTaskObserver.
+func (r *TerminalReporter) Observe(event TaskEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if event.Kind == EventStart {
+		r.running[event.Task] = event.Name
+	} else {
+		delete(r.running, event.Task)
+	}
+	r.render()
+}
+
+// Progress redraws the progress display. It can be passed to WaitWithProgress.
+func (r *TerminalReporter) Progress(done, total int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done, r.total = done, total
+	r.render()
+}
+
+// render redraws the display in place; r.mu must be held
+func (r *TerminalReporter) render() {
+	ids := slices.Sorted(maps.Keys(r.running))
+
+	var lines []string
+	for i, id := range ids {
+		if i == maxSpinnerTasks {
+			lines = append(lines, fmt.Sprintf("  … %d more running", len(ids)-i))
+			break
+		}
+		name := r.running[id]
+		if name == "" {
+			name = fmt.Sprintf("task %d", id)
+		}
+		spinner := spinnerFrames[(r.frame+int(id))%len(spinnerFrames)]
+		lines = append(lines, fmt.Sprintf("  %s %s", spinner, name))
+	}
+
+	filled := progressBarWidth
+	if r.total > 0 {
+		filled = progressBarWidth * r.done / r.total
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	lines = append(lines, fmt.Sprintf("[%s] %d/%d", bar, r.done, r.total))
+	r.frame++
+
+	var b strings.Builder
+	if r.drawn > 1 {
+		fmt.Fprintf(&b, "\x1b[%dA", r.drawn-1)
+	}
+	b.WriteString("\r\x1b[J")
+	b.WriteString(strings.Join(lines, "\n"))
+	io.WriteString(r.w, b.String())
+	r.drawn = len(lines)
+}
+
+// Summary ends the progress display and writes how many tasks of gg succeeded,
+// failed, and panicked, followed by one line per failed task. err is the error
+// returned by Wait; a wait that timed out is reported separately from the tasks.
+func (r *TerminalReporter) Summary(gg *GoroutineGroup, err error) {
+	failures := gg.failures()
+	_, total := gg.batchCounts()
+	var panicked int
+	for _, e := range failures {
+		if errors.Is(e, ErrPanic) {
+			panicked++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n%d tasks: %d succeeded, %d failed, %d panicked",
+		total, max(total-len(failures)-running(gg), 0), len(failures)-panicked, panicked)
+	if n := running(gg); n > 0 {
+		fmt.Fprintf(&b, ", %d still running", n)
+	}
+	b.WriteByte('\n')
+	for _, e := range failures {
+		fmt.Fprintf(&b, "  ✗ %v\n", e)
+	}
+	if errors.Is(err, ErrWaitTimeout) {
+		fmt.Fprintf(&b, "  ⏱ %v\n", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	io.WriteString(r.w, b.String())
+	r.drawn = 0
+}
+
+func running(gg *GoroutineGroup) int {
+	gg.idleMu.Lock()
+	defer gg.idleMu.Unlock()
+	return gg.running
+}
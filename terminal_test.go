@@ -0,0 +1,91 @@
+package goroutine_panic_helper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTerminalReporter(t *testing.T) {
+	var buf bytes.Buffer
+	group := NewGoroutineGroup(context.Background(), func(r interface{}, stack []byte) {})
+	group.Go(func(ctx context.Context) {})
+	group.Go(func(ctx context.Context) {})
+	group.GoErr(func(ctx context.Context) error {
+		return errors.Join(errors.New("migration 3 failed"), errors.New("rollback failed"))
+	})
+	group.Go(func(ctx context.Context) {
+		panic("boom")
+	})
+
+	if err := NewTerminalReporter(&buf).Wait(context.Background(), group); err == nil {
+		t.Error("Expected the group's errors")
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"[" + strings.Repeat("=", progressBarWidth) + "] 4/4",
+		"\n4 tasks: 2 succeeded, 1 failed, 1 panicked\n",
+		"  ✗ migration 3 failed\nrollback failed\n",
+		"  ✗ panic recovery: boom\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in %q", want, out)
+		}
+	}
+}
+
+func TestTerminalReporter_Timeout(t *testing.T) {
+	var buf bytes.Buffer
+	release := make(chan struct{})
+	defer close(release)
+	group := NewGoroutineGroup(context.Background(), nil)
+	group.Go(func(ctx context.Context) {})
+	group.Go(func(ctx context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := NewTerminalReporter(&buf).Wait(ctx, group); !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("Expected a timeout, got: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "\n2 tasks: 1 succeeded, 0 failed, 0 panicked, 1 still running\n") {
+		t.Errorf("Expected the timeout to be kept apart from task failures, got %q", out)
+	}
+	if strings.Contains(out, "✗") || !strings.Contains(out, "  ⏱ wait timed out") {
+		t.Errorf("Expected a single timeout line, got %q", out)
+	}
+}
+
+func TestTerminalReporter_Spinners(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewTerminalReporter(&buf)
+	r.Observe(TaskEvent{Task: 1, Name: "build api", Kind: EventStart})
+	r.Observe(TaskEvent{Task: 2, Kind: EventStart})
+	r.Progress(0, 2)
+	buf.Reset()
+
+	r.Observe(TaskEvent{Task: 1, Kind: EventFinish})
+	want := "\x1b[2A\r\x1b[J  / task 2\n[" + strings.Repeat(" ", 30) + "] 0/2"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestTerminalReporter_Observer(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewTerminalReporter(&buf)
+	group := NewGoroutineGroup(context.Background(), nil, WithTaskObserver(r.Observe))
+	group.GoNamed("scan repo", func(ctx context.Context) {})
+	r.Wait(context.Background(), group)
+
+	if !strings.Contains(buf.String(), " scan repo") {
+		t.Errorf("Expected a spinner for the named task, got %q", buf.String())
+	}
+}
@@ -0,0 +1,23 @@
+package goroutine_panic_helper
+
+import "time"
+
+// Wrap returns a function that calls fn and routes a panic from it through the
+// group's pipeline: the handler, the error returned from Wait, and the group's
+// statistics. The wrapped function runs on whatever goroutine calls it, so this
+// extends coverage to callbacks run by goroutines the group does not start, such
+// as http.Server.RegisterOnShutdown hooks or time.AfterFunc callbacks. Wait does
+// not wait for such callbacks.
+func (gg *GoroutineGroup) Wrap(name string, fn func()) func() {
+	return func() {
+		t := task{name: name, submitted: time.Now()}
+		t.started = t.submitted
+		defer func() {
+			if r := recover(); r != nil {
+				gg.handlePanic(t, r)
+				gg.escalate(r)
+			}
+		}()
+		fn()
+	}
+}
@@ -0,0 +1,34 @@
+package goroutine_panic_helper
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroup_Wrap(t *testing.T) {
+	var handledTask string
+	group := NewGoroutineGroup(context.Background(), nil, WithTaskPanicHandler(func(task string, r interface{}, stack []byte) {
+		handledTask = task
+	}))
+
+	done := make(chan struct{})
+	time.AfterFunc(time.Millisecond, func() {
+		defer close(done)
+		group.Wrap("after-func", func() {
+			panic("callback panic")
+		})()
+	})
+	<-done
+
+	if handledTask != "after-func" {
+		t.Errorf("Expected handler to see the wrapped callback, got %q", handledTask)
+	}
+	if err := group.Wait(); err == nil || !strings.Contains(err.Error(), `task "after-func": callback panic`) {
+		t.Errorf("Expected callback panic in error, got: %v", err)
+	}
+	if group.Snapshot().Panicked != 1 {
+		t.Error("Expected callback panic in group statistics")
+	}
+}